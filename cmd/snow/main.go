@@ -0,0 +1,275 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+// Command snow generates and inspects keys of package
+// [t73f.de/r/zero/snow].
+//
+// Usage:
+//
+//	snow gen [-bits n] [-app n] [-count n] [-format group/sep] [-to enc]
+//	snow decode [-bits n] [-from enc] [-json] [key ...]
+//	snow convert [-from enc] [-to enc] [-format group/sep] [key ...]
+//
+// If no keys are given, decode and convert read them from standard input,
+// separated by white space.
+//
+// Supported encodings are: base32 (default), fixed, checked, hex, base58, and
+// uuid. The format flag applies to the encodings base32 and checked.
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+const usage = `usage:
+  snow gen [-bits n] [-app n] [-count n] [-format group/sep] [-to enc]
+  snow decode [-bits n] [-from enc] [-json] [key ...]
+  snow convert [-from enc] [-to enc] [-format group/sep] [key ...]
+`
+
+// run executes the command with the given arguments and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	var err error
+	switch cmd, cmdArgs := args[0], args[1:]; cmd {
+	case "gen":
+		err = runGen(cmdArgs, stdout, stderr)
+	case "decode":
+		err = runDecode(cmdArgs, stdin, stdout, stderr)
+	case "convert":
+		err = runConvert(cmdArgs, stdin, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n%s", cmd, usage)
+		return 2
+	}
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintln(stderr, "snow:", err)
+		return 1
+	}
+	return 0
+}
+
+func runGen(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("gen", stderr)
+	bits := fs.Uint("bits", 0, "number of application bits")
+	appID := fs.Uint("app", 0, "application value")
+	count := fs.Int("count", 1, "number of keys")
+	format := fs.String("format", "", "group size and separator, e.g. 4/-")
+	to := fs.String("to", "base32", "encoding of the keys")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	layout, err := newLayout(*bits)
+	if err != nil {
+		return err
+	}
+	enc, err := getEncoder(*to, *format)
+	if err != nil {
+		return err
+	}
+	if *count < 0 {
+		return fmt.Errorf("negative count: %d", *count)
+	}
+
+	r, err := snow.New(0, snow.WithLayout(layout)).Reserve(*appID, *count)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	for key := range r.All() {
+		_, _ = w.WriteString(enc(key))
+		_ = w.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+// decodedKey is the JSON representation of a decoded key.
+type decodedKey struct {
+	Key   string    `json:"key"`
+	Time  time.Time `json:"time"`
+	AppID uint      `json:"app"`
+	Seq   uint      `json:"seq"`
+}
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("decode", stderr)
+	bits := fs.Uint("bits", 0, "number of application bits")
+	from := fs.String("from", "base32", "encoding of the keys")
+	asJSON := fs.Bool("json", false, "write JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	layout, err := newLayout(*bits)
+	if err != nil {
+		return err
+	}
+	dec, err := getDecoder(*from)
+	if err != nil {
+		return err
+	}
+	texts, err := keyTexts(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	result := make([]decodedKey, 0, len(texts))
+	for _, text := range texts {
+		key, errDec := dec(text)
+		if errDec != nil {
+			return errDec
+		}
+		parts := layout.Decompose(key)
+		result = append(result, decodedKey{Key: text, Time: parts.Time.UTC(), AppID: parts.AppID, Seq: parts.Seq})
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTIME\tAPP\tSEQ")
+	for _, dk := range result {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", dk.Key, dk.Time.Format(time.RFC3339Nano), dk.AppID, dk.Seq)
+	}
+	return tw.Flush()
+}
+
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	from := fs.String("from", "base32", "encoding of the given keys")
+	to := fs.String("to", "base32", "encoding of the result")
+	format := fs.String("format", "", "group size and separator, e.g. 4/-")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	dec, err := getDecoder(*from)
+	if err != nil {
+		return err
+	}
+	enc, err := getEncoder(*to, *format)
+	if err != nil {
+		return err
+	}
+	texts, err := keyTexts(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	for _, text := range texts {
+		key, errDec := dec(text)
+		if errDec != nil {
+			return errDec
+		}
+		_, _ = w.WriteString(enc(key))
+		_ = w.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("snow "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func newLayout(bits uint) (snow.Layout, error) {
+	return snow.NewLayoutWith(snow.LayoutConfig{AppBits: bits})
+}
+
+// keyTexts returns the given arguments, or all white space separated words
+// of the reader, if there are no arguments.
+func keyTexts(args []string, r io.Reader) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	var result []string
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	for sc.Scan() {
+		result = append(result, sc.Text())
+	}
+	return result, sc.Err()
+}
+
+func getEncoder(name, format string) (func(snow.Key) string, error) {
+	groupSize, sep := 0, ""
+	if format != "" {
+		sGroup, sSep, found := strings.Cut(format, "/")
+		val, err := strconv.Atoi(sGroup)
+		if err != nil || val <= 0 {
+			return nil, fmt.Errorf("invalid group size in format %q", format)
+		}
+		if !found || sSep == "" {
+			return nil, fmt.Errorf("missing separator in format %q, expected group/sep", format)
+		}
+		groupSize, sep = val, sSep
+	}
+	switch name {
+	case "base32":
+		if groupSize == 0 {
+			return snow.Key.String, nil
+		}
+		return func(key snow.Key) string { return key.Format(groupSize, sep) }, nil
+	case "fixed":
+		return snow.Key.FormatFixed, nil
+	case "checked":
+		return func(key snow.Key) string { return key.FormatChecked(cmp.Or(groupSize, snow.FixedLen), sep) }, nil
+	case "hex":
+		return snow.Key.FormatHex, nil
+	case "base58":
+		return snow.Key.FormatBase58, nil
+	case "uuid":
+		return snow.Key.FormatUUID, nil
+	}
+	return nil, fmt.Errorf("unknown encoding %q", name)
+}
+
+func getDecoder(name string) (func(string) (snow.Key, error), error) {
+	switch name {
+	case "base32", "fixed":
+		return snow.Parse, nil
+	case "checked":
+		return snow.ParseChecked, nil
+	case "hex":
+		return snow.ParseHex, nil
+	case "base58":
+		return snow.ParseBase58, nil
+	case "uuid":
+		return snow.ParseUUID, nil
+	}
+	return nil, fmt.Errorf("unknown encoding %q", name)
+}
@@ -0,0 +1,153 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func runCmd(t *testing.T, stdin string, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	if code != 0 {
+		return stderr.String(), code
+	}
+	return stdout.String(), code
+}
+
+func TestGen(t *testing.T) {
+	t.Parallel()
+	out, code := runCmd(t, "", "gen", "-bits", "4", "-app", "5", "-count", "3", "-format", "4/-")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, out)
+	}
+	lines := strings.Fields(out)
+	if len(lines) != 3 {
+		t.Fatalf("three keys expected, but got %q", out)
+	}
+	layout := snow.NewLayout(4)
+	for i, line := range lines {
+		if len(line) != 16 || line[1] != '-' {
+			t.Errorf("formatted key expected, but got %q", line)
+		}
+		key, err := snow.Parse(line)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := layout.AppID(key); got != 5 {
+			t.Errorf("application value 5 expected, but got %d", got)
+		}
+		if got := layout.Seq(key); got != uint(i) {
+			t.Errorf("sequence %d expected, but got %d", i, got)
+		}
+	}
+
+	if out, code = runCmd(t, "", "gen", "-app", "1"); code != 1 {
+		t.Errorf("exit code 1 expected, but got %d: %s", code, out)
+	}
+	if out, code = runCmd(t, "", "gen", "-to", "base64"); code != 1 || !strings.Contains(out, "unknown encoding") {
+		t.Errorf("unknown encoding expected, but got %d: %s", code, out)
+	}
+	for _, format := range []string{"4", "4/", "x/-"} {
+		if out, code = runCmd(t, "", "gen", "-format", format); code != 1 || !strings.Contains(out, "format") {
+			t.Errorf("format error expected for %q, but got %d: %s", format, code, out)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	exp := time.Date(2026, time.January, 2, 3, 4, 5, 6000000, time.UTC)
+	key, err := snow.NewLayout(4).Compose(snow.Parts{Time: exp, AppID: 7, Seq: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, code := runCmd(t, key.Format(4, "-")+"\n"+key.FormatFixed(), "decode", "-bits", "4", "-json")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, out)
+	}
+	var result []decodedKey
+	if err = json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("two keys expected, but got %v", result)
+	}
+	for _, dk := range result {
+		if !dk.Time.Equal(exp) || dk.AppID != 7 || dk.Seq != 42 {
+			t.Errorf("unexpected decoded key: %v", dk)
+		}
+	}
+
+	out, code = runCmd(t, "", "decode", "-bits", "4", "-from", "hex", key.FormatHex())
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, out)
+	}
+	if !strings.Contains(out, "2026-01-02T03:04:05.006Z  7    42") {
+		t.Errorf("table expected, but got %q", out)
+	}
+
+	if out, code = runCmd(t, "", "decode", "0U"); code != 1 {
+		t.Errorf("exit code 1 expected, but got %d: %s", code, out)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	key := snow.Key(507945423712181285)
+	var testcases = []struct {
+		from, to string
+		in, exp  string
+	}{
+		{"base32", "hex", key.String(), key.FormatHex()},
+		{"hex", "base58", key.FormatHex(), key.FormatBase58()},
+		{"base58", "uuid", key.FormatBase58(), key.FormatUUID()},
+		{"uuid", "checked", key.FormatUUID(), key.FormatChecked(snow.FixedLen, "")},
+		{"checked", "fixed", key.FormatChecked(4, "-"), key.FormatFixed()},
+		{"fixed", "base32", key.FormatFixed(), key.String()},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.from+"-"+tc.to, func(t *testing.T) {
+			out, code := runCmd(t, "", "convert", "-from", tc.from, "-to", tc.to, tc.in)
+			if code != 0 {
+				t.Fatalf("exit code %d: %s", code, out)
+			}
+			if got := strings.TrimSpace(out); got != tc.exp {
+				t.Errorf("%q expected, but got %q", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	if _, code := runCmd(t, ""); code != 2 {
+		t.Errorf("exit code 2 expected, but got %d", code)
+	}
+	if _, code := runCmd(t, "", "mint"); code != 2 {
+		t.Errorf("exit code 2 expected, but got %d", code)
+	}
+	if _, code := runCmd(t, "", "gen", "-h"); code != 2 {
+		t.Errorf("exit code 2 expected, but got %d", code)
+	}
+}
@@ -0,0 +1,138 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// TransitiveClosureAll calculates the transitive closure of the whole DAG:
+// there is an edge from one vertex to another, if the other vertex is
+// reachable from the first one.
+//
+// If the digraph is not a DAG, a [CycleError] is returned.
+//
+// The reachability of all vertices is stored in bitsets, so memory usage is
+// quadratic in the number of vertices, but with a small constant factor.
+func (dg Digraph[T]) TransitiveClosureAll() (Digraph[T], error) {
+	order, reach, err := dg.reachability()
+	if err != nil || len(order) == 0 {
+		return nil, err
+	}
+	tc := make(Digraph[T], len(order))
+	for i, vertex := range order {
+		tc[vertex] = nil
+		for j := range reach[i].all() {
+			tc[vertex] = tc[vertex].Add(order[j])
+		}
+	}
+	return tc, nil
+}
+
+// TransitiveReduction calculates the transitive reduction of the DAG: the
+// digraph with the fewest edges, that has the same reachability. An edge is
+// removed, if its to-vertex is also reachable via another path.
+//
+// If the digraph is not a DAG, a [CycleError] is returned.
+func (dg Digraph[T]) TransitiveReduction() (Digraph[T], error) {
+	order, reach, err := dg.reachability()
+	if err != nil || len(order) == 0 {
+		return nil, err
+	}
+	index := make(map[T]int, len(order))
+	for i, vertex := range order {
+		index[vertex] = i
+	}
+	tr := make(Digraph[T], len(order))
+	indirect := newBitset(len(order))
+	for _, vertex := range order {
+		closure := dg[vertex]
+		clear(indirect)
+		for next := range closure.Values() {
+			indirect.or(reach[index[next]])
+		}
+		tr[vertex] = nil
+		for next := range closure.Values() {
+			if !indirect.has(index[next]) {
+				tr[vertex] = tr[vertex].Add(next)
+			}
+		}
+	}
+	return tr, nil
+}
+
+// reachability returns the vertices of the DAG in topological order, and for
+// each vertex the set of the indices of all reachable vertices.
+func (dg Digraph[T]) reachability() ([]T, []bitset, error) {
+	order, err := dg.TopoSort()
+	if err != nil {
+		return nil, nil, err
+	}
+	index := make(map[T]int, len(order))
+	for i, vertex := range order {
+		index[vertex] = i
+	}
+	reach := make([]bitset, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		r := newBitset(len(order))
+		for next := range dg[order[i]].Values() {
+			j := index[next]
+			r.set(j)
+			r.or(reach[j])
+		}
+		reach[i] = r
+	}
+	return order, reach, nil
+}
+
+// bitset is a set of small non-negative integers.
+type bitset []uint64
+
+func newBitset(n int) bitset { return make(bitset, (n+63)/64) }
+
+func (bs bitset) set(i int)      { bs[i/64] |= 1 << (i % 64) }
+func (bs bitset) has(i int) bool { return bs[i/64]&(1<<(i%64)) != 0 }
+
+func (bs bitset) or(other bitset) {
+	for i, w := range other {
+		bs[i] |= w
+	}
+}
+
+func (bs bitset) and(other bitset) {
+	for i, w := range other {
+		bs[i] &= w
+	}
+}
+
+func (bs bitset) andNot(other bitset) {
+	for i, w := range other {
+		bs[i] &^= w
+	}
+}
+
+// all returns an iterator of all elements, in increasing order.
+func (bs bitset) all() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range bs {
+			for w != 0 {
+				if !yield(i*64 + bits.TrailingZeros64(w)) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphTransitiveClosureAll(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		dg   graph.EdgeSlice[int]
+		exp  graph.EdgeSlice[int]
+	}{
+		{"empty", nil, nil},
+		{"single-edge", zps{{1, 2}}, zps{{1, 2}}},
+		{"chain", zps{{1, 2}, {2, 3}, {3, 4}}, zps{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}},
+		{"two-islands", zps{{1, 2}, {2, 3}, {4, 5}}, zps{{1, 2}, {1, 3}, {2, 3}, {4, 5}}},
+		{"direct-indirect", zps{{1, 2}, {1, 3}, {3, 2}}, zps{{1, 2}, {1, 3}, {3, 2}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dg := createDigraph(tc.dg)
+			got, err := dg.TransitiveClosureAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if edges := got.Edges().Sort(); !edges.Equal(tc.exp) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, edges)
+			}
+			if !got.Vertices().Equal(dg.Vertices()) {
+				t.Errorf("expected vertices %v, but got %v", dg.Vertices(), got.Vertices())
+			}
+		})
+	}
+}
+
+func TestDigraphTransitiveReduction(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		dg   graph.EdgeSlice[int]
+		exp  graph.EdgeSlice[int]
+	}{
+		{"empty", nil, nil},
+		{"single-edge", zps{{1, 2}}, zps{{1, 2}}},
+		{"full-chain", zps{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}, zps{{1, 2}, {2, 3}, {3, 4}}},
+		{"direct-indirect", zps{{1, 2}, {1, 3}, {3, 2}}, zps{{1, 3}, {3, 2}}},
+		{"diamond", zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {1, 4}}, zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := createDigraph(tc.dg).TransitiveReduction()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if edges := got.Edges().Sort(); !edges.Equal(tc.exp) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, edges)
+			}
+		})
+	}
+}
+
+func TestDigraphClosureCycle(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 3}, {3, 1}})
+	if _, err := dg.TransitiveClosureAll(); !errors.Is(err, graph.ErrCycle) {
+		t.Errorf("ErrCycle expected, but got %v", err)
+	}
+	if _, err := dg.TransitiveReduction(); !errors.Is(err, graph.ErrCycle) {
+		t.Errorf("ErrCycle expected, but got %v", err)
+	}
+}
+
+func TestDigraphClosureRandom(t *testing.T) {
+	t.Parallel()
+	const n = 300
+	rnd := rand.New(rand.NewPCG(19, 4711))
+	var dg graph.Digraph[int]
+	for from := range n {
+		dg = dg.AddVertex(from)
+		for range rnd.IntN(4) {
+			if to := from + 1 + rnd.IntN(20); to < n {
+				dg = dg.AddVertex(to).AddEdge(from, to)
+			}
+		}
+	}
+	tc, err := dg.TransitiveClosureAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := dg.TransitiveReduction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trc, err := tr.TransitiveClosureAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := range n {
+		exp := dg.ReachableVertices(v)
+		if got := tc[v]; !got.Equal(exp) {
+			t.Errorf("closure of %d: expected %v, but got %v", v, exp, got)
+		}
+		if got := trc[v]; !got.Equal(exp) {
+			t.Errorf("reduction of %d changes reachability: expected %v, but got %v", v, exp, got)
+		}
+		for next := range tr[v].Values() {
+			if !dg[v].Contains(next) {
+				t.Errorf("reduction contains new edge %d->%d", v, next)
+			}
+		}
+	}
+}
+
+func BenchmarkDigraphTransitiveReduction(b *testing.B) {
+	const n = 20000
+	var dg graph.Digraph[int]
+	for from := range n {
+		dg = dg.AddVertex(from)
+		for _, delta := range []int{1, 2, 5} {
+			if to := from + delta; to < n {
+				dg = dg.AddVertex(to).AddEdge(from, to)
+			}
+		}
+	}
+	for b.Loop() {
+		if _, err := dg.TransitiveReduction(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DigraphDiff describes the changes from one digraph to another. All slices
+// are sorted.
+type DigraphDiff[T cmp.Ordered] struct {
+	AddedVertices   []T
+	RemovedVertices []T
+	AddedEdges      EdgeSlice[T]
+	RemovedEdges    EdgeSlice[T]
+}
+
+// Diff calculates the changes from the old digraph to the new digraph. The
+// edges of a removed vertex are listed as removed edges, and the edges of an
+// added vertex are listed as added edges.
+func Diff[T cmp.Ordered](oldDg, newDg Digraph[T]) (d DigraphDiff[T]) {
+	for vertex, closure := range newDg {
+		oldClosure, found := oldDg[vertex]
+		if !found {
+			d.AddedVertices = append(d.AddedVertices, vertex)
+		}
+		for next := range closure.Values() {
+			if !oldClosure.Contains(next) {
+				d.AddedEdges = append(d.AddedEdges, Edge[T]{From: vertex, To: next})
+			}
+		}
+	}
+	for vertex, closure := range oldDg {
+		newClosure, found := newDg[vertex]
+		if !found {
+			d.RemovedVertices = append(d.RemovedVertices, vertex)
+		}
+		for next := range closure.Values() {
+			if !newClosure.Contains(next) {
+				d.RemovedEdges = append(d.RemovedEdges, Edge[T]{From: vertex, To: next})
+			}
+		}
+	}
+	slices.Sort(d.AddedVertices)
+	slices.Sort(d.RemovedVertices)
+	d.AddedEdges.Sort()
+	d.RemovedEdges.Sort()
+	return d
+}
+
+// IsEmpty returns true, if the diff contains no changes.
+func (d DigraphDiff[T]) IsEmpty() bool {
+	return len(d.AddedVertices) == 0 && len(d.RemovedVertices) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Reverse returns the diff that undoes the changes of this diff.
+func (d DigraphDiff[T]) Reverse() DigraphDiff[T] {
+	return DigraphDiff[T]{
+		AddedVertices:   d.RemovedVertices,
+		RemovedVertices: d.AddedVertices,
+		AddedEdges:      d.RemovedEdges,
+		RemovedEdges:    d.AddedEdges,
+	}
+}
+
+// Patch applies the changes of the diff to the digraph: edges and vertices
+// are removed first, then vertices and edges are added. Removing a missing
+// vertex or edge, and adding an existing one, has no effect. Therefore,
+// patching the old digraph of [Diff] results in the new digraph.
+//
+// The digraph is modified, use [Digraph.Clone] to keep the original.
+func (dg Digraph[T]) Patch(d DigraphDiff[T]) Digraph[T] {
+	for _, edge := range d.RemovedEdges {
+		if closure, found := dg[edge.From]; found {
+			dg[edge.From] = closure.Remove(edge.To)
+		}
+	}
+	for _, vertex := range d.RemovedVertices {
+		dg.RemoveVertex(vertex)
+	}
+	for _, vertex := range d.AddedVertices {
+		dg = dg.AddVertex(vertex)
+	}
+	return dg.AddEgdes(d.AddedEdges)
+}
+
+// WriteText writes the diff in a line-oriented, human-readable form: first
+// the vertices, then the edges, each prefixed with "+" if added, or "-" if
+// removed.
+func (d DigraphDiff[T]) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, v := range d.AddedVertices {
+		fmt.Fprintf(bw, "+ %v\n", v)
+	}
+	for _, v := range d.RemovedVertices {
+		fmt.Fprintf(bw, "- %v\n", v)
+	}
+	for _, edge := range d.AddedEdges {
+		fmt.Fprintf(bw, "+ %v -> %v\n", edge.From, edge.To)
+	}
+	for _, edge := range d.RemovedEdges {
+		fmt.Fprintf(bw, "- %v -> %v\n", edge.From, edge.To)
+	}
+	return bw.Flush()
+}
+
+// String returns the diff in the form of [DigraphDiff.WriteText].
+func (d DigraphDiff[T]) String() string {
+	var sb strings.Builder
+	_ = d.WriteText(&sb)
+	return sb.String()
+}
+
+// WriteDOT writes the union of the given digraph and the diff in the
+// Graphviz DOT language, where added vertices and edges are drawn in green,
+// and removed ones are drawn in red and dashed. The given digraph may be the
+// old or the new digraph of [Diff].
+//
+// The options are applied as in [Digraph.WriteDOT], and may be nil.
+// Attributes returned by their callbacks override the attributes of the diff.
+func (d DigraphDiff[T]) WriteDOT(w io.Writer, dg Digraph[T], opts *DOTOptions[T]) error {
+	union := dg.Clone()
+	for _, v := range d.AddedVertices {
+		union = union.AddVertex(v)
+	}
+	for _, v := range d.RemovedVertices {
+		union = union.AddVertex(v)
+	}
+	union = union.AddEgdes(d.AddedEdges).AddEgdes(d.RemovedEdges)
+
+	added := map[string]string{"color": "green"}
+	removed := map[string]string{"color": "red", "style": "dashed"}
+	var addedEdges, removedEdges Digraph[T]
+	addedEdges = addedEdges.AddEgdes(d.AddedEdges)
+	removedEdges = removedEdges.AddEgdes(d.RemovedEdges)
+
+	overlay := DOTOptions[T]{}
+	if opts != nil {
+		overlay = *opts
+	}
+	merge := func(attrs, user map[string]string) map[string]string {
+		if attrs == nil {
+			return user
+		}
+		result := maps.Clone(attrs)
+		maps.Copy(result, user)
+		return result
+	}
+	overlay.VertexAttrs = func(v T) (attrs map[string]string) {
+		switch {
+		case containsSorted(d.AddedVertices, v):
+			attrs = added
+		case containsSorted(d.RemovedVertices, v):
+			attrs = removed
+		}
+		if opts != nil && opts.VertexAttrs != nil {
+			return merge(attrs, opts.VertexAttrs(v))
+		}
+		return attrs
+	}
+	overlay.EdgeAttrs = func(from, to T) (attrs map[string]string) {
+		switch {
+		case addedEdges[from].Contains(to):
+			attrs = added
+		case removedEdges[from].Contains(to):
+			attrs = removed
+		}
+		if opts != nil && opts.EdgeAttrs != nil {
+			return merge(attrs, opts.EdgeAttrs(from, to))
+		}
+		return attrs
+	}
+	return union.WriteDOT(w, &overlay)
+}
+
+func containsSorted[T cmp.Ordered](s []T, v T) bool {
+	_, found := slices.BinarySearch(s, v)
+	return found
+}
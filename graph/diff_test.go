@@ -0,0 +1,121 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	oldDg := createDigraph(zps{{1, 2}, {2, 3}, {3, 4}})
+	newDg := createDigraph(zps{{1, 2}, {2, 4}, {4, 5}, {1, 5}})
+	d := graph.Diff(oldDg, newDg)
+
+	if exp := []int{5}; !slices.Equal(d.AddedVertices, exp) {
+		t.Errorf("added vertices: expected %v, but got %v", exp, d.AddedVertices)
+	}
+	if exp := []int{3}; !slices.Equal(d.RemovedVertices, exp) {
+		t.Errorf("removed vertices: expected %v, but got %v", exp, d.RemovedVertices)
+	}
+	if exp := (zps{{1, 5}, {2, 4}, {4, 5}}); !d.AddedEdges.Equal(exp) {
+		t.Errorf("added edges: expected %v, but got %v", exp, d.AddedEdges)
+	}
+	if exp := (zps{{2, 3}, {3, 4}}); !d.RemovedEdges.Equal(exp) {
+		t.Errorf("removed edges: expected %v, but got %v", exp, d.RemovedEdges)
+	}
+	if d.IsEmpty() {
+		t.Error("diff must not be empty")
+	}
+
+	expText := "+ 5\n- 3\n+ 1 -> 5\n+ 2 -> 4\n+ 4 -> 5\n- 2 -> 3\n- 3 -> 4\n"
+	if got := d.String(); got != expText {
+		t.Errorf("expected text:\n%s\nbut got:\n%s", expText, got)
+	}
+
+	if got := oldDg.Clone().Patch(d); !got.Equal(newDg) {
+		t.Errorf("patch: expected %v, but got %v", newDg, got)
+	}
+	if got := newDg.Clone().Patch(d.Reverse()); !got.Equal(oldDg) {
+		t.Errorf("reverse patch: expected %v, but got %v", oldDg, got)
+	}
+	if got := graph.Diff(newDg, newDg); !got.IsEmpty() {
+		t.Errorf("expected empty diff, but got:\n%v", got)
+	}
+}
+
+func TestDiffRandom(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewPCG(25, 42))
+	randomDigraph := func() (dg graph.Digraph[int]) {
+		for range 20 {
+			dg = dg.AddEgdes(zps{{rng.IntN(10), rng.IntN(10)}})
+		}
+		return dg
+	}
+	for range 100 {
+		oldDg, newDg := randomDigraph(), randomDigraph()
+		d := graph.Diff(oldDg, newDg)
+		if got := oldDg.Clone().Patch(d); !got.Equal(newDg) {
+			t.Fatalf("patch %v with\n%v\nexpected %v, but got %v", oldDg, d, newDg, got)
+		}
+		if got := newDg.Clone().Patch(d.Reverse()); !got.Equal(oldDg) {
+			t.Fatalf("patch %v with reversed\n%v\nexpected %v, but got %v", newDg, d, oldDg, got)
+		}
+	}
+}
+
+func TestDiffWriteDOT(t *testing.T) {
+	t.Parallel()
+	oldDg := createDigraph(zps{{1, 2}, {2, 3}, {3, 4}})
+	newDg := createDigraph(zps{{1, 2}, {2, 4}, {4, 5}, {1, 5}})
+	d := graph.Diff(oldDg, newDg)
+	exp := `digraph "changes" {
+  "1" [shape="box"];
+  "2";
+  "3" [color="red", style="dashed"];
+  "4";
+  "5" [color="green"];
+  "1" -> "2";
+  "1" -> "5" [color="green"];
+  "2" -> "3" [color="red", style="dashed"];
+  "2" -> "4" [color="green"];
+  "3" -> "4" [color="red", style="dashed"];
+  "4" -> "5" [color="green"];
+}
+`
+	opts := &graph.DOTOptions[int]{
+		Name: "changes",
+		VertexAttrs: func(v int) map[string]string {
+			if v == 1 {
+				return map[string]string{"shape": "box"}
+			}
+			return nil
+		},
+	}
+	for _, dg := range []graph.Digraph[int]{oldDg, newDg} {
+		var sb strings.Builder
+		if err := d.WriteDOT(&sb, dg, opts); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != exp {
+			t.Errorf("expected:\n%s\nbut got:\n%s", exp, got)
+		}
+	}
+}
@@ -11,7 +11,7 @@
 // SPDX-FileCopyrightText: 2023-present Detlef Stern
 //-----------------------------------------------------------------------------
 
-// Package graph implements a (directed) graph of orderable values.
+// Package graph implements directed and undirected graphs of orderable values.
 package graph
 
 import (
@@ -197,17 +197,6 @@ func (dg Digraph[T]) ReachableVertices(startV T) (tc *set.Set[T]) {
 	return tc
 }
 
-// IsDAG returns a vertex and false, if the graph has a cycle containing the vertex.
-func (dg Digraph[T]) IsDAG() (T, bool) {
-	for vertex := range dg {
-		if dg.ReachableVertices(vertex).Contains(vertex) {
-			return vertex, false
-		}
-	}
-	var zeroT T
-	return zeroT, true
-}
-
 // Reverse returns a graph with reversed edges.
 func (dg Digraph[T]) Reverse() (revDg Digraph[T]) {
 	for vertex, closure := range dg {
@@ -223,7 +212,7 @@ func (dg Digraph[T]) Reverse() (revDg Digraph[T]) {
 // SortReverse returns a deterministic, topological, reverse sort of the digraph.
 //
 // Works only if digraph is a DAG. Otherwise the algorithm will not terminate
-// or returns an arbitrary value.
+// or returns an arbitrary value. Use [Digraph.TopoLayers] to detect cycles.
 func (dg Digraph[T]) SortReverse() (sl []T) {
 	if len(dg) == 0 {
 		return nil
@@ -0,0 +1,164 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Dominators calculates the immediate dominator of every vertex that is
+// reachable from the entry vertex, with the algorithm of Cooper, Harvey, and
+// Kennedy. A vertex `d` dominates a vertex `v`, if every path from the entry
+// to `v` contains `d`. The immediate dominator of `v` is the dominator of `v`
+// that is dominated by all other dominators of `v`, except `v` itself.
+//
+// The result maps every vertex to its immediate dominator, i.e. it is the
+// dominator tree, rooted at the entry. The entry vertex itself, and all vertices
+// that are not reachable from it, are not contained. If the entry is not a
+// vertex of the digraph, nil is returned.
+//
+// The digraph may contain cycles.
+func (dg Digraph[T]) Dominators(entry T) map[T]T {
+	if !dg.HasVertex(entry) {
+		return nil
+	}
+	var post []T
+	for v := range dg.DFSPostOrder(entry, nil) {
+		post = append(post, v)
+	}
+	num := make(map[T]int, len(post))
+	for i, v := range post {
+		num[v] = i
+	}
+	preds := make([][]int, len(post))
+	for i, v := range post {
+		for next := range dg[v].Values() {
+			preds[num[next]] = append(preds[num[next]], i)
+		}
+	}
+
+	// Vertices are identified by their postorder number, so the entry has the
+	// largest number, and a dominator has a larger number than the vertices it
+	// dominates.
+	root := len(post) - 1
+	idom := make([]int, len(post))
+	for i := range idom {
+		idom[i] = -1
+	}
+	idom[root] = root
+	intersect := func(a, b int) int {
+		for a != b {
+			for a < b {
+				a = idom[a]
+			}
+			for b < a {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := root - 1; i >= 0; i-- {
+			newIdom := -1
+			for _, p := range preds[i] {
+				if idom[p] < 0 {
+					continue
+				}
+				if newIdom < 0 {
+					newIdom = p
+				} else {
+					newIdom = intersect(p, newIdom)
+				}
+			}
+			if idom[i] != newIdom {
+				idom[i] = newIdom
+				changed = true
+			}
+		}
+	}
+
+	result := make(map[T]T, root)
+	for i, d := range idom[:root] {
+		result[post[i]] = post[d]
+	}
+	return result
+}
+
+// LCAIndex answers lowest common ancestor queries for a DAG. A vertex is an
+// ancestor of another vertex, if the other vertex is reachable from it. Every
+// vertex is an ancestor of itself.
+//
+// The ancestors of all vertices are stored in bitsets, so memory usage is
+// quadratic in the number of vertices, but with a small constant factor.
+type LCAIndex[T cmp.Ordered] struct {
+	order     []T
+	index     map[T]int
+	ancestors []bitset // strict ancestors, by index
+}
+
+// NewLCAIndex builds the lowest common ancestor index of the given DAG. If the
+// digraph is not a DAG, a [CycleError] is returned.
+func NewLCAIndex[T cmp.Ordered](dg Digraph[T]) (*LCAIndex[T], error) {
+	order, ancestors, err := dg.Reverse().reachability()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[T]int, len(order))
+	for i, vertex := range order {
+		index[vertex] = i
+	}
+	return &LCAIndex[T]{order: order, index: index, ancestors: ancestors}, nil
+}
+
+// IsAncestor returns true, if `v` is reachable from `a`, or if both are the
+// same vertex of the DAG.
+func (li *LCAIndex[T]) IsAncestor(a, v T) bool {
+	ia, foundA := li.index[a]
+	iv, foundV := li.index[v]
+	return foundA && foundV && (ia == iv || li.ancestors[iv].has(ia))
+}
+
+// LCA returns the lowest common ancestors of `u` and `v`: all common
+// ancestors, from which no other common ancestor is reachable. In contrast to
+// a tree, there may be more than one lowest common ancestor, or none at all.
+// The result is sorted. If `u` or `v` is not a vertex of the DAG, nil is
+// returned.
+func (li *LCAIndex[T]) LCA(u, v T) []T {
+	iu, foundU := li.index[u]
+	iv, foundV := li.index[v]
+	if !foundU || !foundV {
+		return nil
+	}
+	common := newBitset(len(li.order))
+	common.or(li.ancestors[iu])
+	common.set(iu)
+	other := slices.Clone(li.ancestors[iv])
+	other.set(iv)
+	common.and(other)
+
+	covered := newBitset(len(li.order))
+	for i := range common.all() {
+		covered.or(li.ancestors[i])
+	}
+	common.andNot(covered)
+
+	var result []T
+	for i := range common.all() {
+		result = append(result, li.order[i])
+	}
+	slices.Sort(result)
+	return result
+}
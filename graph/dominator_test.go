@@ -0,0 +1,150 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphDominators(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name  string
+		dg    graph.EdgeSlice[int]
+		entry int
+		exp   map[int]int
+	}{
+		{"empty", nil, 1, nil},
+		{"no-entry", zps{{1, 2}}, 3, nil},
+		{"single", zps{{1, 2}}, 2, map[int]int{}},
+		{"chain", zps{{1, 2}, {2, 3}}, 1, map[int]int{2: 1, 3: 2}},
+		{"diamond", zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}}, 1, map[int]int{2: 1, 3: 1, 4: 1}},
+		{"loop", zps{{1, 2}, {2, 3}, {2, 4}, {3, 5}, {4, 5}, {5, 2}, {5, 6}, {7, 1}}, 1,
+			map[int]int{2: 1, 3: 2, 4: 2, 5: 2, 6: 5}},
+		{"irreducible", zps{{6, 5}, {6, 4}, {5, 1}, {4, 2}, {4, 3}, {1, 2}, {2, 1}, {2, 3}, {3, 2}}, 6,
+			map[int]int{1: 6, 2: 6, 3: 6, 4: 6, 5: 6}},
+		{"gatekeeper", zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}, {4, 6}, {5, 7}, {6, 7}}, 1,
+			map[int]int{2: 1, 3: 1, 4: 1, 5: 4, 6: 4, 7: 4}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := createDigraph(tc.dg).Dominators(tc.entry)
+			if !maps.Equal(got, tc.exp) || (got == nil) != (tc.exp == nil) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestDigraphDominatorsRandom(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewPCG(23, 42))
+	for range 50 {
+		var edges graph.EdgeSlice[int]
+		for range 30 {
+			edges = append(edges, graph.Edge[int]{From: rng.IntN(15), To: rng.IntN(15)})
+		}
+		dg := graph.Digraph[int](nil).AddEgdes(edges).AddVertex(0)
+		idom := dg.Dominators(0)
+
+		// d strictly dominates v, if v is not reachable from the entry without d.
+		reachable := dg.ReachableVertices(0)
+		dominates := func(d, v int) bool {
+			if d == 0 {
+				return true
+			}
+			without := dg.Clone()
+			without.RemoveVertex(d)
+			return !without.ReachableVertices(0).Contains(v)
+		}
+		count := 0
+		for v := range reachable.Values() {
+			if v == 0 {
+				continue
+			}
+			count++
+			d, found := idom[v]
+			if !found {
+				t.Fatalf("%v: no immediate dominator of %d", edges, v)
+			}
+			if !dominates(d, v) {
+				t.Fatalf("%v: %d does not dominate %d", edges, d, v)
+			}
+			for other := range reachable.Values() {
+				if other != v && other != d && dominates(other, v) && !dominates(other, d) {
+					t.Fatalf("%v: %d dominates %d, but not %d", edges, other, v, d)
+				}
+			}
+		}
+		if len(idom) != count {
+			t.Fatalf("%v: unexpected dominators %v for reachable %v", edges, idom, reachable)
+		}
+	}
+}
+
+func TestLCAIndex(t *testing.T) {
+	t.Parallel()
+	dg := graph.Digraph[int](nil).AddEgdes(zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {2, 5}, {3, 5}, {6, 5}})
+	li, err := graph.NewLCAIndex(dg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testcases := []struct {
+		u, v int
+		exp  []int
+	}{
+		{4, 5, []int{2, 3}},
+		{5, 4, []int{2, 3}},
+		{4, 2, []int{2}},
+		{4, 4, []int{4}},
+		{2, 3, []int{1}},
+		{4, 6, nil},
+		{5, 6, []int{6}},
+		{1, 6, nil},
+		{4, 99, nil},
+	}
+	for _, tc := range testcases {
+		if got := li.LCA(tc.u, tc.v); !slices.Equal(got, tc.exp) {
+			t.Errorf("LCA(%d, %d): expected %v, but got %v", tc.u, tc.v, tc.exp, got)
+		}
+	}
+
+	if !li.IsAncestor(1, 5) || !li.IsAncestor(5, 5) {
+		t.Error("1 and 5 must be ancestors of 5")
+	}
+	if li.IsAncestor(5, 1) || li.IsAncestor(6, 4) || li.IsAncestor(99, 99) {
+		t.Error("unexpected ancestor")
+	}
+}
+
+func TestLCAIndexCycle(t *testing.T) {
+	t.Parallel()
+	dg := graph.Digraph[int](nil).AddEgdes(zps{{1, 2}, {2, 3}, {3, 2}})
+	if _, err := graph.NewLCAIndex(dg); !errors.Is(err, graph.ErrCycle) {
+		t.Errorf("expected cycle error, but got %v", err)
+	}
+	li, err := graph.NewLCAIndex(graph.Digraph[int](nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := li.LCA(1, 1); got != nil {
+		t.Errorf("expected no LCA in empty DAG, but got %v", got)
+	}
+}
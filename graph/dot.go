@@ -0,0 +1,418 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DOTOptions customize the output of [Digraph.WriteDOT]. The zero value
+// produces a plain digraph.
+type DOTOptions[T cmp.Ordered] struct {
+	// Name of the graph. Default: "G".
+	Name string
+
+	// VertexAttrs returns the Graphviz attributes of a vertex, e.g. "label"
+	// or "shape". May be nil.
+	VertexAttrs func(v T) map[string]string
+
+	// EdgeAttrs returns the Graphviz attributes of an edge. May be nil.
+	EdgeAttrs func(from, to T) map[string]string
+
+	// Highlight contains the vertices and edges that are drawn in the
+	// highlight color, e.g. the result of [Digraph.TransitiveClosure].
+	Highlight Digraph[T]
+
+	// HighlightColor is the color of highlighted vertices and edges.
+	// Default: "red".
+	HighlightColor string
+}
+
+// WriteDOT writes the digraph in the Graphviz DOT language. Vertices and edges
+// are written in sorted order, so the output is deterministic. The options may
+// be nil.
+//
+// Attributes returned by the callbacks of the options override the color
+// attribute of highlighted vertices and edges.
+func (dg Digraph[T]) WriteDOT(w io.Writer, opts *DOTOptions[T]) error {
+	if opts == nil {
+		opts = &DOTOptions[T]{}
+	}
+	highlight := func(attrs map[string]string) map[string]string {
+		result := map[string]string{"color": cmp.Or(opts.HighlightColor, "red")}
+		maps.Copy(result, attrs)
+		return result
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", quoteDOT(cmp.Or(opts.Name, "G")))
+	for _, v := range slices.Sorted(maps.Keys(dg)) {
+		var attrs map[string]string
+		if opts.VertexAttrs != nil {
+			attrs = opts.VertexAttrs(v)
+		}
+		if opts.Highlight.HasVertex(v) {
+			attrs = highlight(attrs)
+		}
+		fmt.Fprintf(bw, "  %s%s;\n", quoteDOT(fmt.Sprint(v)), formatDOTAttrs(attrs))
+	}
+	for _, edge := range dg.Edges().Sort() {
+		var attrs map[string]string
+		if opts.EdgeAttrs != nil {
+			attrs = opts.EdgeAttrs(edge.From, edge.To)
+		}
+		if opts.Highlight[edge.From].Contains(edge.To) {
+			attrs = highlight(attrs)
+		}
+		fmt.Fprintf(bw, "  %s -> %s%s;\n",
+			quoteDOT(fmt.Sprint(edge.From)), quoteDOT(fmt.Sprint(edge.To)), formatDOTAttrs(attrs))
+	}
+	_, _ = bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// formatDOTID returns the identifier unquoted, if it is a valid DOT
+// identifier, and quoted otherwise.
+func formatDOTID(s string) string {
+	if s == "" || isDigit(s[0]) || isDOTKeyword(s) {
+		return quoteDOT(s)
+	}
+	for i := range len(s) {
+		if !isDOTLetter(s[i]) && !isDigit(s[i]) {
+			return quoteDOT(s)
+		}
+	}
+	return s
+}
+
+func formatDOTAttrs(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	_, _ = sb.WriteString(" [")
+	for i, key := range slices.Sorted(maps.Keys(attrs)) {
+		if i > 0 {
+			_, _ = sb.WriteString(", ")
+		}
+		_, _ = sb.WriteString(formatDOTID(key))
+		_ = sb.WriteByte('=')
+		_, _ = sb.WriteString(quoteDOT(attrs[key]))
+	}
+	_ = sb.WriteByte(']')
+	return sb.String()
+}
+
+// ErrDOTSyntax signals an invalid or unsupported DOT input.
+var ErrDOTSyntax = errors.New("DOT syntax error")
+
+// ParseDOT parses a digraph in a simple subset of the Graphviz DOT language,
+// e.g. as written by [Digraph.WriteDOT]. Vertices are returned as strings.
+//
+// Supported are vertex statements, edge statements with chains of "->", and
+// comments. Identifiers are alphanumeric strings not starting with a digit,
+// numerals, or double-quoted strings. Keywords are case-insensitive and must
+// be quoted to be used as vertices. Attributes, graph attributes, and node /
+// edge default statements are accepted, but ignored. Subgraphs, undirected
+// edges, ports, and HTML strings are not supported.
+func ParseDOT(src string) (Digraph[string], error) {
+	return ParseDOTFunc(src, func(s string) (string, error) { return s, nil })
+}
+
+// ParseDOTFunc parses a digraph like [ParseDOT], but converts all vertex
+// identifiers with the given function.
+func ParseDOTFunc[T cmp.Ordered](src string, conv func(string) (T, error)) (Digraph[T], error) {
+	p := dotParser{src: src}
+	if err := p.header(); err != nil {
+		return nil, err
+	}
+	var dg Digraph[T]
+	vertex := func(id string) (T, error) {
+		v, err := conv(id)
+		if err != nil {
+			return v, fmt.Errorf("%w: vertex %q: %w", ErrDOTSyntax, id, err)
+		}
+		dg = dg.AddVertex(v)
+		return v, nil
+	}
+	for {
+		tok, kind, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case kind != dotPunct:
+		case tok == "}":
+			if tok, _, err = p.next(); err != nil {
+				return nil, err
+			}
+			if tok != "" {
+				return nil, p.errorf("unexpected %q after graph", tok)
+			}
+			return dg, nil
+		case tok == ";":
+			continue
+		case tok == "":
+			return nil, p.errorf("unexpected end of input")
+		default:
+			return nil, p.errorf("unexpected %q", tok)
+		}
+
+		if p.peek() == "=" { // Graph attribute
+			_, _, _ = p.next()
+			if _, kind, err = p.next(); err != nil || kind == dotPunct {
+				return nil, p.errorf("attribute value expected")
+			}
+			continue
+		}
+		if kind == dotID && isDOTKeyword(tok) {
+			if strings.EqualFold(tok, "subgraph") {
+				return nil, p.errorf("subgraphs are not supported")
+			}
+			if !strings.EqualFold(tok, "digraph") && !strings.EqualFold(tok, "strict") && p.peek() == "[" {
+				if err = p.attrs(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, p.errorf("unexpected keyword %q", tok)
+		}
+
+		from, err := vertex(tok)
+		if err != nil {
+			return nil, err
+		}
+		for p.peek() == "->" {
+			_, _, _ = p.next()
+			tok, kind, err = p.next()
+			if err != nil || kind == dotPunct || (kind == dotID && isDOTKeyword(tok)) {
+				return nil, p.errorf("vertex expected after \"->\"")
+			}
+			to, errV := vertex(tok)
+			if errV != nil {
+				return nil, errV
+			}
+			dg = dg.AddEdge(from, to)
+			from = to
+		}
+		if p.peek() == "[" {
+			if err = p.attrs(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// dotKind is the kind of a DOT token.
+type dotKind uint8
+
+const (
+	dotPunct  dotKind = iota // Punctuation, or end of input
+	dotID                    // Unquoted identifier or numeral
+	dotQuoted                // Double-quoted string
+)
+
+// dotParser is a tokenizer for DOT input.
+type dotParser struct {
+	src string
+	pos int
+}
+
+func (p *dotParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrDOTSyntax, p.pos, fmt.Sprintf(format, args...))
+}
+
+// header parses everything up to the opening brace.
+func (p *dotParser) header() error {
+	tok, kind, err := p.next()
+	if err != nil {
+		return err
+	}
+	if kind == dotID && strings.EqualFold(tok, "strict") {
+		if tok, kind, err = p.next(); err != nil {
+			return err
+		}
+	}
+	if kind != dotID || !strings.EqualFold(tok, "digraph") {
+		return p.errorf("\"digraph\" expected, but got %q", tok)
+	}
+	if tok, kind, err = p.next(); err != nil {
+		return err
+	}
+	if kind == dotQuoted || (kind == dotID && !isDOTKeyword(tok)) {
+		if tok, _, err = p.next(); err != nil {
+			return err
+		}
+	}
+	if tok != "{" {
+		return p.errorf("\"{\" expected, but got %q", tok)
+	}
+	return nil
+}
+
+// attrs skips an attribute list.
+func (p *dotParser) attrs() error {
+	_, _, _ = p.next() // "["
+	for {
+		tok, _, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case "]":
+			return nil
+		case "":
+			return p.errorf("unterminated attribute list")
+		}
+	}
+}
+
+// peek returns the next token without consuming it.
+func (p *dotParser) peek() string {
+	pos := p.pos
+	tok, _, _ := p.next()
+	p.pos = pos
+	return tok
+}
+
+// next returns the next token and its kind. At the end of the input, an empty
+// string is returned.
+func (p *dotParser) next() (string, dotKind, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return "", dotPunct, nil
+	}
+	start := p.pos
+	switch ch := p.src[p.pos]; {
+	case ch == '"':
+		var sb strings.Builder
+		for p.pos++; p.pos < len(p.src); p.pos++ {
+			switch ch = p.src[p.pos]; ch {
+			case '"':
+				p.pos++
+				return sb.String(), dotQuoted, nil
+			case '\\':
+				if p.pos+1 < len(p.src) && (p.src[p.pos+1] == '"' || p.src[p.pos+1] == '\\') {
+					p.pos++
+					ch = p.src[p.pos]
+				}
+			}
+			_ = sb.WriteByte(ch)
+		}
+		p.pos = start
+		return "", dotPunct, p.errorf("unterminated string")
+	case strings.HasPrefix(p.src[p.pos:], "->"):
+		p.pos += 2
+		return "->", dotPunct, nil
+	case strings.HasPrefix(p.src[p.pos:], "--"):
+		return "", dotPunct, p.errorf("undirected edge \"--\" is not supported")
+	case strings.IndexByte("{}[];,=", ch) >= 0:
+		p.pos++
+		return p.src[start:p.pos], dotPunct, nil
+	case isDOTLetter(ch):
+		for p.pos < len(p.src) && (isDOTLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		return p.src[start:p.pos], dotID, nil
+	case ch == '-' || ch == '.' || isDigit(ch):
+		return p.numeral()
+	default:
+		return "", dotPunct, p.errorf("unexpected character %q", ch)
+	}
+}
+
+// numeral parses a DOT numeral: [-]?(.[0-9]+ | [0-9]+(.[0-9]*)?)
+func (p *dotParser) numeral() (string, dotKind, error) {
+	start := p.pos
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := p.skipDigits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		digits += p.skipDigits()
+	}
+	if digits == 0 {
+		p.pos = start
+		return "", dotPunct, p.errorf("unexpected character %q", p.src[start])
+	}
+	if p.pos < len(p.src) {
+		if ch := p.src[p.pos]; isDOTLetter(ch) || isDigit(ch) || ch == '.' {
+			p.pos = start
+			return "", dotPunct, p.errorf("invalid numeral")
+		}
+	}
+	return p.src[start:p.pos], dotID, nil
+}
+
+func (p *dotParser) skipDigits() int {
+	start := p.pos
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+func (p *dotParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch rest := p.src[p.pos:]; {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			p.pos++
+		case strings.HasPrefix(rest, "//") || rest[0] == '#':
+			if end := strings.IndexByte(rest, '\n'); end >= 0 {
+				p.pos += end + 1
+			} else {
+				p.pos = len(p.src)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			if end := strings.Index(rest[2:], "*/"); end >= 0 {
+				p.pos += end + 4
+			} else {
+				p.pos = len(p.src)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// isDOTKeyword returns true, if the identifier is a DOT keyword. Keywords are
+// case-insensitive.
+func isDOTKeyword(s string) bool {
+	for _, kw := range []string{"node", "edge", "graph", "digraph", "subgraph", "strict"} {
+		if strings.EqualFold(s, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDOTLetter returns true, if the character may start an identifier. Bytes of
+// multi-byte UTF-8 characters are treated as letters.
+func isDOTLetter(ch byte) bool {
+	return ch == '_' || ch >= 0x80 || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+}
+
+func isDigit(ch byte) bool { return '0' <= ch && ch <= '9' }
@@ -0,0 +1,203 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphWriteDOT(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 3}, {1, 3}, {4, 5}})
+	var sb strings.Builder
+	err := dg.WriteDOT(&sb, &graph.DOTOptions[int]{
+		Name: "deps",
+		VertexAttrs: func(v int) map[string]string {
+			if v == 4 {
+				return map[string]string{"shape": "box", "label": `"four"`}
+			}
+			return nil
+		},
+		EdgeAttrs: func(from, to int) map[string]string {
+			if from == 1 && to == 3 {
+				return map[string]string{"style": "dashed", "color": "blue", "font size": "8"}
+			}
+			return nil
+		},
+		Highlight: dg.TransitiveClosure(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `digraph "deps" {
+  "1";
+  "2" [color="red"];
+  "3" [color="red"];
+  "4" [label="\"four\"", shape="box"];
+  "5";
+  "1" -> "2";
+  "1" -> "3" [color="blue", "font size"="8", style="dashed"];
+  "2" -> "3" [color="red"];
+  "4" -> "5";
+}
+`
+	if got := sb.String(); got != exp {
+		t.Errorf("expected:\n%s\nbut got:\n%s", exp, got)
+	}
+}
+
+func TestDigraphDOTRoundTrip(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		dg   graph.EdgeSlice[int]
+	}{
+		{"empty", nil},
+		{"single-loop", zps{{1, 1}}},
+		{"two-islands", zps{{1, 2}, {2, 3}, {4, 5}}},
+		{"negative", zps{{-1, 2}, {2, -3}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dg := createDigraph(tc.dg).AddVertex(7)
+			var sb strings.Builder
+			if err := dg.WriteDOT(&sb, nil); err != nil {
+				t.Fatal(err)
+			}
+			got, err := graph.ParseDOTFunc(sb.String(), strconv.Atoi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(dg) {
+				t.Errorf("expected %v, but got %v from:\n%s", dg, got, sb.String())
+			}
+		})
+	}
+}
+
+func TestDigraphDOTRoundTripKeywords(t *testing.T) {
+	t.Parallel()
+	dg := graph.Digraph[string](nil).AddEdge("edge", "graph").
+		AddVertex("graph").AddVertex("node").AddVertex("Node").AddVertex("x")
+	var sb strings.Builder
+	if err := dg.WriteDOT(&sb, &graph.DOTOptions[string]{Highlight: dg}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := graph.ParseDOT(sb.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(dg) {
+		t.Errorf("expected %v, but got %v from:\n%s", dg, got, sb.String())
+	}
+}
+
+func TestParseDOT(t *testing.T) {
+	t.Parallel()
+	src := `/* comment */ strict digraph deps {
+	rankdir=LR; // comment
+	node [shape=box];
+	EDGE [color=red]; Graph [rankdir=TB]
+	# another comment
+	a -> b -> "c \"quoted\"" [label="x"]
+	d
+	b -> a;
+	_x1 -> -1.5 -> .5 -> 2.
+}`
+	dg, err := graph.ParseDOT(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := graph.EdgeSlice[string]{{"-1.5", ".5"}, {".5", "2."}, {"_x1", "-1.5"}, {"a", "b"}, {"b", "a"}, {"b", `c "quoted"`}}
+	if got := dg.Edges().Sort(); !got.Equal(exp) {
+		t.Errorf("expected %v, but got %v", exp, got)
+	}
+	if !dg.HasVertex("d") {
+		t.Error("vertex d expected")
+	}
+	if got := len(dg); got != 8 {
+		t.Errorf("expected 8 vertices, but got %d: %v", got, dg)
+	}
+
+	for _, src = range []string{
+		"",
+		"graph { a -- b }",
+		"digraph { a -> }",
+		"digraph { a -> b",
+		"digraph { a [label=x }",
+		`digraph { "a }`,
+		"digraph { a } b",
+		"digraph { subgraph { a } }",
+		"digraph { SubGraph x { a } }",
+		"digraph { node }",
+		"digraph { a -> edge }",
+		`"digraph" { a }`,
+		"digraph { a -- b }",
+		"digraph { a-b }",
+		"digraph { a - b }",
+		"digraph { 1a }",
+		"digraph { 1.2.3 }",
+		"digraph { - }",
+		"digraph { . }",
+		"digraph { a:n -> b }",
+		"digraph { <a> }",
+		`digraph { a } "b`,
+	} {
+		if _, err = graph.ParseDOT(src); !errors.Is(err, graph.ErrDOTSyntax) {
+			t.Errorf("syntax error expected for %q, but got %v", src, err)
+		}
+	}
+	if _, err = graph.ParseDOTFunc("digraph { a }", strconv.Atoi); !errors.Is(err, graph.ErrDOTSyntax) {
+		t.Errorf("conversion error expected, but got %v", err)
+	}
+}
+
+func TestDigraphJSON(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 3}, {1, 2}, {2, 3}}).AddVertex(4)
+	data, err := json.Marshal(dg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"1":[2,3],"2":[3],"3":[],"4":[]}`; string(data) != exp {
+		t.Errorf("expected %s, but got %s", exp, data)
+	}
+	var got graph.Digraph[int]
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(dg) {
+		t.Errorf("expected %v, but got %v", dg, got)
+	}
+
+	if err = json.Unmarshal([]byte(`{"a":["b"]}`), &got); err == nil {
+		t.Error("error expected for string vertices")
+	}
+	var sdg graph.Digraph[string]
+	if err = json.Unmarshal([]byte(`{"a":["b"]}`), &sdg); err != nil {
+		t.Fatal(err)
+	}
+	if exp := (graph.EdgeSlice[string]{{"a", "b"}}); !sdg.Edges().Equal(exp) || !sdg.HasVertex("b") {
+		t.Errorf("expected %v, but got %v", exp, sdg)
+	}
+	if data, _ = json.Marshal(graph.Digraph[int](nil)); string(data) != "null" {
+		t.Errorf("null expected, but got %s", data)
+	}
+}
@@ -0,0 +1,56 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MarshalJSON encodes the digraph as a JSON object, that maps every vertex to
+// the sorted list of its successors, e.g. {"1":[2,3],"2":[],"3":[]}.
+//
+// Since JSON object keys are strings, vertices must be strings or integers.
+func (dg Digraph[T]) MarshalJSON() ([]byte, error) {
+	if dg == nil {
+		return []byte("null"), nil
+	}
+	adj := make(map[T][]T, len(dg))
+	for vertex, closure := range dg {
+		succs := slices.Sorted(closure.Values())
+		if succs == nil {
+			succs = []T{}
+		}
+		adj[vertex] = succs
+	}
+	return json.Marshal(adj)
+}
+
+// UnmarshalJSON decodes a digraph, as encoded by [Digraph.MarshalJSON]. A
+// successor that is not a key of the JSON object is added as a vertex.
+func (dg *Digraph[T]) UnmarshalJSON(data []byte) error {
+	var adj map[T][]T
+	if err := json.Unmarshal(data, &adj); err != nil {
+		return err
+	}
+	var result Digraph[T]
+	for vertex, succs := range adj {
+		result = result.AddVertex(vertex)
+		for _, next := range succs {
+			result = result.AddVertex(next).AddEdge(vertex, next)
+		}
+	}
+	*dg = result
+	return nil
+}
@@ -0,0 +1,118 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import "cmp"
+
+// LabeledDigraph is a digraph, where every vertex has a payload of type V,
+// and every edge has a label of type E, e.g. the kind of a dependency.
+//
+// Filtered views return a plain [Digraph], so that all its algorithms can be
+// applied.
+//
+// The zero value is an empty labeled digraph, ready to use.
+type LabeledDigraph[T cmp.Ordered, V, E any] struct {
+	dg       Digraph[T]
+	payloads map[T]V
+	labels   map[Edge[T]]E
+}
+
+// AddVertex adds a vertex with the given payload. If the vertex already
+// exists, its payload is replaced.
+func (lg *LabeledDigraph[T, V, E]) AddVertex(v T, payload V) *LabeledDigraph[T, V, E] {
+	lg.dg = lg.dg.AddVertex(v)
+	if lg.payloads == nil {
+		lg.payloads = map[T]V{}
+	}
+	lg.payloads[v] = payload
+	return lg
+}
+
+// AddEdge adds a connection from `from` to `to` with the given label. In
+// contrast to [Digraph.AddEdge] the vertices must not exist before; new
+// vertices get the zero value as payload. If the edge already exists, its
+// label is replaced.
+func (lg *LabeledDigraph[T, V, E]) AddEdge(from, to T, label E) *LabeledDigraph[T, V, E] {
+	lg.dg = lg.dg.AddVertex(from).AddVertex(to).AddEdge(from, to)
+	if lg.labels == nil {
+		lg.labels = map[Edge[T]]E{}
+	}
+	lg.labels[Edge[T]{From: from, To: to}] = label
+	return lg
+}
+
+// RemoveVertex removes a vertex, its payload, and all its edges.
+func (lg *LabeledDigraph[T, V, E]) RemoveVertex(v T) {
+	if !lg.dg.HasVertex(v) {
+		return
+	}
+	for edge := range lg.labels {
+		if edge.From == v || edge.To == v {
+			delete(lg.labels, edge)
+		}
+	}
+	delete(lg.payloads, v)
+	lg.dg.RemoveVertex(v)
+}
+
+// Payload returns the payload of the given vertex, and true if the vertex
+// exists.
+func (lg *LabeledDigraph[T, V, E]) Payload(v T) (V, bool) {
+	if !lg.dg.HasVertex(v) {
+		var zeroV V
+		return zeroV, false
+	}
+	return lg.payloads[v], true
+}
+
+// Label returns the label of the edge from `from` to `to`, and true if the
+// edge exists.
+func (lg *LabeledDigraph[T, V, E]) Label(from, to T) (E, bool) {
+	label, found := lg.labels[Edge[T]{From: from, To: to}]
+	return label, found
+}
+
+// Digraph returns the underlying digraph, without payloads and labels. It
+// must not be modified.
+func (lg *LabeledDigraph[T, V, E]) Digraph() Digraph[T] { return lg.dg }
+
+// FilterEdges returns a digraph with all vertices, but only with those edges,
+// for which the given function returns true.
+func (lg *LabeledDigraph[T, V, E]) FilterEdges(keep func(from, to T, label E) bool) (result Digraph[T]) {
+	for vertex := range lg.dg {
+		result = result.AddVertex(vertex)
+	}
+	for edge, label := range lg.labels {
+		if keep(edge.From, edge.To, label) {
+			result = result.AddEdge(edge.From, edge.To)
+		}
+	}
+	return result
+}
+
+// FilterVertices returns the digraph of those vertices, for which the given
+// function returns true, and of all edges between them.
+func (lg *LabeledDigraph[T, V, E]) FilterVertices(keep func(v T, payload V) bool) (result Digraph[T]) {
+	for vertex := range lg.dg {
+		if keep(vertex, lg.payloads[vertex]) {
+			result = result.AddVertex(vertex)
+		}
+	}
+	for edge := range lg.labels {
+		if result.HasVertex(edge.From) && result.HasVertex(edge.To) {
+			result = result.AddEdge(edge.From, edge.To)
+		}
+	}
+	return result
+}
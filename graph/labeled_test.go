@@ -0,0 +1,107 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"slices"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+	"t73f.de/r/zero/set"
+)
+
+type module struct {
+	version string
+	vendor  bool
+}
+
+func createModules() *graph.LabeledDigraph[string, module, string] {
+	var lg graph.LabeledDigraph[string, module, string]
+	lg.AddVertex("app", module{version: "1.0"})
+	lg.AddVertex("db", module{version: "2.1", vendor: true})
+	lg.AddVertex("log", module{version: "0.9"})
+	lg.AddEdge("app", "db", "requires")
+	lg.AddEdge("app", "log", "optional")
+	lg.AddEdge("db", "log", "requires")
+	lg.AddEdge("app", "mock", "test-only")
+	return &lg
+}
+
+func TestLabeledDigraph(t *testing.T) {
+	t.Parallel()
+	lg := createModules()
+	if got, found := lg.Payload("db"); !found || got.version != "2.1" {
+		t.Errorf("payload of db expected, but got %v/%v", got, found)
+	}
+	if got, found := lg.Payload("mock"); !found || got != (module{}) {
+		t.Errorf("zero payload of mock expected, but got %v/%v", got, found)
+	}
+	if _, found := lg.Payload("web"); found {
+		t.Error("web must not be found")
+	}
+	if got, found := lg.Label("app", "log"); !found || got != "optional" {
+		t.Errorf("label optional expected, but got %q/%v", got, found)
+	}
+	if _, found := lg.Label("log", "app"); found {
+		t.Error("edge log->app must not be found")
+	}
+
+	lg.AddEdge("app", "log", "requires")
+	if got, _ := lg.Label("app", "log"); got != "requires" {
+		t.Errorf("label should be replaced, but got %q", got)
+	}
+	exp := graph.EdgeSlice[string]{{"app", "db"}, {"app", "log"}, {"app", "mock"}, {"db", "log"}}
+	if got := lg.Digraph().Edges().Sort(); !got.Equal(exp) {
+		t.Errorf("expected %v, but got %v", exp, got)
+	}
+
+	lg.RemoveVertex("log")
+	if _, found := lg.Label("db", "log"); found || lg.Digraph().HasVertex("log") {
+		t.Error("log must be removed")
+	}
+	exp = graph.EdgeSlice[string]{{"app", "db"}, {"app", "mock"}}
+	if got := lg.Digraph().Edges().Sort(); !got.Equal(exp) {
+		t.Errorf("expected %v, but got %v", exp, got)
+	}
+}
+
+func TestLabeledDigraphFilter(t *testing.T) {
+	t.Parallel()
+	lg := createModules()
+	requires := lg.FilterEdges(func(_, _ string, label string) bool { return label == "requires" })
+	exp := graph.EdgeSlice[string]{{"app", "db"}, {"db", "log"}}
+	if got := requires.Edges().Sort(); !got.Equal(exp) {
+		t.Errorf("expected %v, but got %v", exp, got)
+	}
+	if !requires.Vertices().Equal(lg.Digraph().Vertices()) {
+		t.Errorf("all vertices expected, but got %v", requires.Vertices())
+	}
+	if got := requires.ReachableVertices("app"); !got.Equal(set.New("db", "log")) {
+		t.Errorf("existing algorithms should apply, but got %v", got)
+	}
+
+	own := lg.FilterVertices(func(_ string, m module) bool { return !m.vendor })
+	exp = graph.EdgeSlice[string]{{"app", "log"}, {"app", "mock"}}
+	if got := own.Edges().Sort(); !got.Equal(exp) {
+		t.Errorf("expected %v, but got %v", exp, got)
+	}
+	if got := slices.Sorted(own.Vertices().Values()); !slices.Equal(got, []string{"app", "log", "mock"}) {
+		t.Errorf("non-vendor vertices expected, but got %v", got)
+	}
+
+	var empty graph.LabeledDigraph[int, string, int]
+	if got := empty.FilterEdges(func(_, _, _ int) bool { return true }); got != nil {
+		t.Errorf("nil digraph expected, but got %v", got)
+	}
+}
@@ -0,0 +1,197 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"maps"
+	"slices"
+)
+
+// StronglyConnectedComponents returns all strongly connected components of
+// the digraph, computed with the algorithm of Tarjan. Within a component, all
+// vertices are reachable from each other. Every vertex belongs to exactly one
+// component.
+//
+// The vertices of a component are sorted. Components are returned in reverse
+// topological order: if there is an edge from component A to component B, B
+// is returned before A.
+func (dg Digraph[T]) StronglyConnectedComponents() [][]T {
+	if len(dg) == 0 {
+		return nil
+	}
+	type frame struct {
+		vertex T
+		succs  []T
+		pos    int
+	}
+	index := make(map[T]int, len(dg))
+	lowlink := make(map[T]int, len(dg))
+	onStack := make(map[T]bool, len(dg))
+	var stack []T
+	var result [][]T
+
+	visit := func(v T, frames []frame) []frame {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		return append(frames, frame{vertex: v, succs: slices.Sorted(dg[v].Values())})
+	}
+
+	for _, root := range slices.Sorted(maps.Keys(dg)) {
+		if _, found := index[root]; found {
+			continue
+		}
+		frames := visit(root, nil)
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			if top.pos < len(top.succs) {
+				next := top.succs[top.pos]
+				top.pos++
+				if _, found := index[next]; !found {
+					frames = visit(next, frames)
+				} else if onStack[next] {
+					lowlink[top.vertex] = min(lowlink[top.vertex], index[next])
+				}
+				continue
+			}
+
+			v := top.vertex
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].vertex
+				lowlink[parent] = min(lowlink[parent], lowlink[v])
+			}
+			if lowlink[v] == index[v] {
+				var comp []T
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					comp = append(comp, w)
+					if w == v {
+						break
+					}
+				}
+				slices.Sort(comp)
+				result = append(result, comp)
+			}
+		}
+	}
+	return result
+}
+
+// Condensation returns the digraph of all strongly connected components. Each
+// component is represented by its smallest vertex. There is an edge between
+// two representatives, if there is an edge between vertices of their
+// components. The resulting digraph is always a DAG.
+//
+// The returned map relates every vertex to the representative of its
+// component.
+func (dg Digraph[T]) Condensation() (Digraph[T], map[T]T) {
+	if len(dg) == 0 {
+		return nil, nil
+	}
+	rep := make(map[T]T, len(dg))
+	var cdg Digraph[T]
+	for _, comp := range dg.StronglyConnectedComponents() {
+		for _, v := range comp {
+			rep[v] = comp[0]
+		}
+		cdg = cdg.AddVertex(comp[0])
+	}
+	for vertex, closure := range dg {
+		for next := range closure.Values() {
+			if from, to := rep[vertex], rep[next]; from != to {
+				cdg = cdg.AddEdge(from, to)
+			}
+		}
+	}
+	return cdg, rep
+}
+
+// FindCycle returns the vertices of a cycle in the digraph, in the order of
+// their edges: there is an edge from every vertex to the next one, and from
+// the last vertex to the first one. A self-loop is returned as a cycle with
+// one vertex. If the digraph is a DAG, nil is returned.
+//
+// The search is deterministic: vertices and their successors are visited in
+// sorted order.
+func (dg Digraph[T]) FindCycle() []T { return dg.findCycle(true) }
+
+// IsDAG returns a vertex and false, if the graph has a cycle containing the vertex.
+func (dg Digraph[T]) IsDAG() (T, bool) {
+	if cycle := dg.findCycle(false); len(cycle) > 0 {
+		return cycle[0], false
+	}
+	var zeroT T
+	return zeroT, true
+}
+
+// findCycle performs a depth-first search to find a cycle. If sorted is
+// false, the search order depends on map iteration, but runs in linear time.
+func (dg Digraph[T]) findCycle(sorted bool) []T {
+	const (
+		white = iota // vertex not visited
+		grey         // vertex on current path
+		black        // vertex and all its successors visited
+	)
+	type frame struct {
+		vertex T
+		succs  []T
+		pos    int
+	}
+	successors := func(v T) []T {
+		if sorted {
+			return slices.Sorted(dg[v].Values())
+		}
+		return slices.Collect(dg[v].Values())
+	}
+	roots := slices.Collect(maps.Keys(dg))
+	if sorted {
+		slices.Sort(roots)
+	}
+
+	color := make(map[T]int, len(dg))
+	for _, root := range roots {
+		if color[root] != white {
+			continue
+		}
+		color[root] = grey
+		frames := []frame{{vertex: root, succs: successors(root)}}
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			if top.pos >= len(top.succs) {
+				color[top.vertex] = black
+				frames = frames[:len(frames)-1]
+				continue
+			}
+			next := top.succs[top.pos]
+			top.pos++
+			switch color[next] {
+			case white:
+				color[next] = grey
+				frames = append(frames, frame{vertex: next, succs: successors(next)})
+			case grey:
+				pos := slices.IndexFunc(frames, func(f frame) bool { return f.vertex == next })
+				cycle := make([]T, 0, len(frames)-pos)
+				for _, f := range frames[pos:] {
+					cycle = append(cycle, f.vertex)
+				}
+				return cycle
+			}
+		}
+	}
+	return nil
+}
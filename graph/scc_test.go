@@ -0,0 +1,115 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"slices"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphStronglyConnectedComponents(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		dg   graph.EdgeSlice[int]
+		exp  [][]int
+	}{
+		{"empty", nil, nil},
+		{"single-edge", zps{{1, 2}}, [][]int{{2}, {1}}},
+		{"single-loop", zps{{1, 1}}, [][]int{{1}}},
+		{"long-loop", zps{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 2}}, [][]int{{2, 3, 4, 5}, {1}}},
+		{"two-loops", zps{{1, 2}, {2, 1}, {2, 3}, {3, 4}, {4, 3}, {5, 4}}, [][]int{{3, 4}, {1, 2}, {5}}},
+		{"two-islands", zps{{1, 2}, {2, 3}, {4, 5}}, [][]int{{3}, {2}, {1}, {5}, {4}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := createDigraph(tc.dg).StronglyConnectedComponents()
+			if !slices.EqualFunc(got, tc.exp, slices.Equal) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestDigraphCondensation(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 1}, {2, 3}, {3, 4}, {4, 3}, {5, 4}, {1, 4}})
+	cdg, rep := dg.Condensation()
+	if exp := (zps{{1, 3}, {5, 3}}); !cdg.Edges().Sort().Equal(exp) {
+		t.Errorf("expected edges %v, but got %v", exp, cdg.Edges().Sort())
+	}
+	if _, isDAG := cdg.IsDAG(); !isDAG {
+		t.Error("condensation must be a DAG")
+	}
+	for v, exp := range map[int]int{1: 1, 2: 1, 3: 3, 4: 3, 5: 5} {
+		if got := rep[v]; got != exp {
+			t.Errorf("representative of %d should be %d, but got %d", v, exp, got)
+		}
+	}
+}
+
+func TestDigraphFindCycle(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		dg   graph.EdgeSlice[int]
+		exp  []int
+	}{
+		{"empty", nil, nil},
+		{"single-edge", zps{{1, 2}}, nil},
+		{"single-loop", zps{{1, 1}}, []int{1}},
+		{"end-loop", zps{{1, 2}, {2, 2}}, []int{2}},
+		{"long-loop", zps{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 2}}, []int{2, 3, 4, 5}},
+		{"sect-loop", zps{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {4, 2}}, []int{2, 3, 4}},
+		{"direct-indirect", zps{{1, 2}, {1, 3}, {3, 2}}, nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dg := createDigraph(tc.dg)
+			got := dg.FindCycle()
+			if !slices.Equal(got, tc.exp) {
+				t.Errorf("expected %v, but got %v", tc.exp, got)
+			}
+			for i, v := range got {
+				if next := got[(i+1)%len(got)]; !dg[v].Contains(next) {
+					t.Errorf("cycle %v: missing edge %d->%d", got, v, next)
+				}
+			}
+			if v, isDAG := dg.IsDAG(); isDAG != (tc.exp == nil) {
+				t.Errorf("IsDAG should be %v, but got %v (%v)", tc.exp == nil, isDAG, v)
+			}
+		})
+	}
+}
+
+func TestIsDAGLarge(t *testing.T) {
+	t.Parallel()
+	const n = 100000
+	var dg graph.Digraph[int]
+	for i := range n {
+		dg = dg.AddVertex(i).AddVertex(i+1).AddEdge(i, i+1)
+	}
+	if v, isDAG := dg.IsDAG(); !isDAG {
+		t.Errorf("chain must be a DAG, but got cycle at %d", v)
+	}
+	dg.AddEdge(n, 0)
+	if cycle := dg.FindCycle(); len(cycle) != n+1 {
+		t.Errorf("cycle of length %d expected, but got %d", n+1, len(cycle))
+	}
+	if comps := dg.StronglyConnectedComponents(); len(comps) != 1 {
+		t.Errorf("one component expected, but got %d", len(comps))
+	}
+}
@@ -0,0 +1,200 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// TaskStatus is the final status of a task, executed by [Digraph.Schedule].
+type TaskStatus uint8
+
+// Constants for TaskStatus.
+const (
+	TaskSucceeded TaskStatus = iota // Task returned without an error.
+	TaskFailed                      // Task returned an error.
+	TaskSkipped                     // Task was not run, because a dependency failed.
+	TaskCanceled                    // Task was not run or stopped, because the schedule was stopped.
+)
+
+var taskStatusNames = [...]string{
+	TaskSucceeded: "succeeded",
+	TaskFailed:    "failed",
+	TaskSkipped:   "skipped",
+	TaskCanceled:  "canceled",
+}
+
+func (ts TaskStatus) String() string {
+	if int(ts) < len(taskStatusNames) {
+		return taskStatusNames[ts]
+	}
+	return fmt.Sprintf("TaskStatus(%d)", ts)
+}
+
+// TaskResult reports the execution of a task.
+type TaskResult struct {
+	Status   TaskStatus
+	Err      error         // Error returned by the task, if status is TaskFailed.
+	Start    time.Time     // Start time, if the task was run.
+	Duration time.Duration // Run time, if the task was run.
+}
+
+// ScheduleOption customizes [Digraph.Schedule].
+type ScheduleOption func(*scheduleConfig)
+
+type scheduleConfig struct {
+	limit           int
+	continueOnError bool
+}
+
+// WithConcurrency sets the maximum number of tasks that run at the same time.
+// Default: the number of usable CPUs, as reported by [runtime.GOMAXPROCS].
+func WithConcurrency(n int) ScheduleOption {
+	return func(cfg *scheduleConfig) { cfg.limit = n }
+}
+
+// WithContinueOnError lets the schedule run all tasks, whose dependencies
+// succeeded, even if another task failed. By default, the schedule stops after
+// the first failed task, and cancels the context of all running tasks.
+func WithContinueOnError() ScheduleOption {
+	return func(cfg *scheduleConfig) { cfg.continueOnError = true }
+}
+
+// Schedule runs the given task for every vertex of the DAG, in parallel. An
+// edge from one vertex to another states that the first vertex depends on the
+// second: the task of the second vertex must finish successfully, before the
+// task of the first vertex starts. If there is a choice, smaller vertices are
+// started first.
+//
+// The schedule stops, if the context is canceled. Tasks that were not run get
+// the status [TaskSkipped], if a dependency failed or was skipped, or
+// [TaskCanceled] otherwise. A running task that returns the error of its
+// canceled context gets the status [TaskCanceled] too, and its error is not
+// reported.
+//
+// Schedule returns the results of all tasks, together with all task errors
+// and the error of the context, joined by [errors.Join]. If the digraph is not
+// a DAG, only a [CycleError] is returned.
+func (dg Digraph[T]) Schedule(ctx context.Context, task func(context.Context, T) error, opts ...ScheduleOption) (map[T]TaskResult, error) {
+	cfg := scheduleConfig{limit: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.limit = max(cfg.limit, 1)
+	layers, err := dg.TopoLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type done struct {
+		vertex T
+		result TaskResult
+	}
+	finished := make(chan done, cfg.limit)
+	results := make(map[T]TaskResult, len(dg))
+	pending := make(map[T]int, len(dg))
+	ready := vertexHeap[T]{cmp: cmp.Compare[T]}
+	for vertex, closure := range dg {
+		if n := closure.Length(); n > 0 {
+			pending[vertex] = n
+		} else {
+			ready.vs = append(ready.vs, vertex)
+		}
+	}
+	heap.Init(&ready)
+	dependents := dg.Reverse()
+
+	running, stopped := 0, false
+	for {
+		for !stopped && running < cfg.limit && ready.Len() > 0 {
+			if runCtx.Err() != nil {
+				stopped = true
+				break
+			}
+			vertex := heap.Pop(&ready).(T)
+			running++
+			go func() {
+				start := time.Now()
+				err := task(runCtx, vertex)
+				result := TaskResult{Start: start, Duration: time.Since(start)}
+				if ctxErr := runCtx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+					result.Status = TaskCanceled
+				} else if err != nil {
+					result.Status = TaskFailed
+					result.Err = err
+				}
+				finished <- done{vertex: vertex, result: result}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		d := <-finished
+		running--
+		results[d.vertex] = d.result
+		switch d.result.Status {
+		case TaskFailed:
+			if !cfg.continueOnError {
+				stopped = true
+				cancel()
+			}
+			continue
+		case TaskCanceled:
+			continue
+		}
+		for dependent := range dependents[d.vertex].Values() {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				heap.Push(&ready, dependent)
+			}
+		}
+	}
+
+	for _, layer := range layers {
+		for _, vertex := range layer {
+			if _, found := results[vertex]; found {
+				continue
+			}
+			status := TaskCanceled
+			for dep := range dg[vertex].Values() {
+				if s := results[dep].Status; s == TaskFailed || s == TaskSkipped {
+					status = TaskSkipped
+					break
+				}
+			}
+			results[vertex] = TaskResult{Status: status}
+		}
+	}
+
+	var errs []error
+	for _, vertex := range slices.Sorted(maps.Keys(results)) {
+		if err = results[vertex].Err; err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", vertex, err))
+		}
+	}
+	errs = append(errs, ctx.Err())
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,228 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphScheduleOrder(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {5, 6}})
+	var mx sync.Mutex
+	var order []int
+	results, err := dg.Schedule(t.Context(), func(_ context.Context, v int) error {
+		mx.Lock()
+		order = append(order, v)
+		mx.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(dg) {
+		t.Errorf("expected %d results, but got %d", len(dg), len(results))
+	}
+	pos := make(map[int]int, len(order))
+	for i, v := range order {
+		pos[v] = i
+	}
+	for _, edge := range dg.Edges() {
+		if pos[edge.From] < pos[edge.To] {
+			t.Errorf("%d started before its dependency %d: %v", edge.From, edge.To, order)
+		}
+	}
+	for v, res := range results {
+		if res.Status != graph.TaskSucceeded || res.Err != nil || res.Start.IsZero() {
+			t.Errorf("vertex %d: unexpected result %+v", v, res)
+		}
+	}
+}
+
+func TestDigraphScheduleSequential(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {3, 4}, {5, 2}})
+	var order []int
+	_, err := dg.Schedule(t.Context(), func(_ context.Context, v int) error {
+		order = append(order, v)
+		return nil
+	}, graph.WithConcurrency(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []int{2, 1, 4, 3, 5}; !slices.Equal(order, exp) {
+		t.Errorf("expected order %v, but got %v", exp, order)
+	}
+}
+
+func TestDigraphScheduleConcurrency(t *testing.T) {
+	t.Parallel()
+	var dg graph.Digraph[int]
+	for v := range 20 {
+		dg = dg.AddVertex(v)
+	}
+	const limit = 3
+	var running, peak atomic.Int32
+	_, err := dg.Schedule(t.Context(), func(context.Context, int) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return nil
+	}, graph.WithConcurrency(limit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("expected at most %d concurrent tasks, but got %d", limit, got)
+	}
+}
+
+func TestDigraphScheduleErrors(t *testing.T) {
+	t.Parallel()
+	errTask := errors.New("task failed")
+	// 1 and 3 depend on 2, which fails; 4 depends on 1.
+	dg := createDigraph(zps{{1, 2}, {3, 2}, {4, 1}, {5, 6}})
+	task := func(_ context.Context, v int) error {
+		if v == 2 {
+			return errTask
+		}
+		return nil
+	}
+
+	testcases := []struct {
+		name string
+		opts []graph.ScheduleOption
+		exp  map[int]graph.TaskStatus
+	}{
+		{"fail-fast", []graph.ScheduleOption{graph.WithConcurrency(1)}, map[int]graph.TaskStatus{
+			1: graph.TaskSkipped, 2: graph.TaskFailed, 3: graph.TaskSkipped, 4: graph.TaskSkipped,
+			5: graph.TaskCanceled, 6: graph.TaskCanceled,
+		}},
+		{"continue", []graph.ScheduleOption{graph.WithConcurrency(1), graph.WithContinueOnError()}, map[int]graph.TaskStatus{
+			1: graph.TaskSkipped, 2: graph.TaskFailed, 3: graph.TaskSkipped, 4: graph.TaskSkipped,
+			5: graph.TaskSucceeded, 6: graph.TaskSucceeded,
+		}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := dg.Schedule(t.Context(), task, tc.opts...)
+			if !errors.Is(err, errTask) {
+				t.Errorf("expected error %v, but got %v", errTask, err)
+			}
+			for v, exp := range tc.exp {
+				if got := results[v].Status; got != exp {
+					t.Errorf("vertex %d: expected status %v, but got %v", v, exp, got)
+				}
+			}
+			if got := results[2].Err; got != errTask {
+				t.Errorf("expected task error %v, but got %v", errTask, got)
+			}
+		})
+	}
+}
+
+func TestDigraphScheduleCancel(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 3}})
+	ctx, cancel := context.WithCancel(t.Context())
+	results, err := dg.Schedule(ctx, func(_ context.Context, v int) error {
+		if v == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, but got %v", context.Canceled, err)
+	}
+	exp := map[int]graph.TaskStatus{1: graph.TaskCanceled, 2: graph.TaskCanceled, 3: graph.TaskSucceeded}
+	for v, s := range exp {
+		if got := results[v].Status; got != s {
+			t.Errorf("vertex %d: expected status %v, but got %v", v, s, got)
+		}
+	}
+}
+
+func TestDigraphScheduleCancelRunning(t *testing.T) {
+	t.Parallel()
+	errTask := errors.New("task failed")
+	// 1 depends on 2, 3 depends on 4; 4 runs until its context is canceled.
+	dg := createDigraph(zps{{1, 2}, {3, 4}})
+
+	testcases := []struct {
+		name    string
+		stop    func(context.CancelFunc) error
+		expErr  error
+		expStat graph.TaskStatus
+	}{
+		{"fail-fast", func(context.CancelFunc) error { return errTask }, errTask, graph.TaskSkipped},
+		{"parent", func(cancel context.CancelFunc) error { cancel(); return nil }, context.Canceled, graph.TaskCanceled},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			results, err := dg.Schedule(ctx, func(ctx context.Context, v int) error {
+				if v == 2 {
+					return tc.stop(cancel)
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			}, graph.WithConcurrency(2))
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, but got %v", tc.expErr, err)
+			}
+			if tc.expErr != context.Canceled && errors.Is(err, context.Canceled) {
+				t.Errorf("error of canceled task must not be reported: %v", err)
+			}
+			if got := results[1].Status; got != tc.expStat {
+				t.Errorf("vertex 1: expected status %v, but got %v", tc.expStat, got)
+			}
+			if res := results[4]; res.Status != graph.TaskCanceled || res.Err != nil || res.Start.IsZero() {
+				t.Errorf("vertex 4: expected canceled after start, but got %+v", res)
+			}
+			if got := results[3].Status; got != graph.TaskCanceled {
+				t.Errorf("vertex 3: expected status %v, but got %v", graph.TaskCanceled, got)
+			}
+		})
+	}
+}
+
+func TestDigraphScheduleCycle(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 1}})
+	results, err := dg.Schedule(t.Context(), func(context.Context, int) error {
+		t.Error("task must not be called")
+		return nil
+	})
+	if !errors.Is(err, graph.ErrCycle) {
+		t.Errorf("expected cycle error, but got %v", err)
+	}
+	if results != nil {
+		t.Errorf("expected no results, but got %v", results)
+	}
+}
@@ -0,0 +1,172 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+
+	"t73f.de/r/zero/set"
+)
+
+// ErrCycle signals a cycle in a graph that must be acyclic.
+var ErrCycle = errors.New("graph has a cycle")
+
+// CycleError is returned, if an algorithm requires a DAG, but the digraph
+// contains a cycle.
+type CycleError[T cmp.Ordered] struct {
+	// Cycle contains the vertices of one cycle, in the order of their edges,
+	// as returned by [Digraph.FindCycle].
+	Cycle []T
+}
+
+func (e *CycleError[T]) Error() string { return fmt.Sprintf("%v: %v", ErrCycle, e.Cycle) }
+
+// Unwrap returns [ErrCycle].
+func (e *CycleError[T]) Unwrap() error { return ErrCycle }
+
+// TopoSort returns a deterministic topological sort of the digraph: for every
+// edge, its from-vertex is placed before its to-vertex. If there is a choice,
+// the smallest vertex is placed first.
+//
+// If the digraph is not a DAG, a [CycleError] is returned.
+func (dg Digraph[T]) TopoSort() ([]T, error) { return dg.TopoSortFunc(cmp.Compare[T]) }
+
+// TopoSortFunc returns a topological sort of the digraph, like
+// [Digraph.TopoSort]. If there is a choice, the vertex that is smallest
+// according to the given comparison function is placed first.
+func (dg Digraph[T]) TopoSortFunc(cmpFn func(T, T) int) ([]T, error) {
+	if len(dg) == 0 {
+		return nil, nil
+	}
+	indegree := make(map[T]int, len(dg))
+	for _, closure := range dg {
+		for next := range closure.Values() {
+			indegree[next]++
+		}
+	}
+	ready := vertexHeap[T]{cmp: cmpFn}
+	for v := range dg {
+		if indegree[v] == 0 {
+			ready.vs = append(ready.vs, v)
+		}
+	}
+	heap.Init(&ready)
+	order := make([]T, 0, len(dg))
+	for ready.Len() > 0 {
+		v := heap.Pop(&ready).(T)
+		order = append(order, v)
+		for next := range dg[v].Values() {
+			indegree[next]--
+			if indegree[next] == 0 {
+				heap.Push(&ready, next)
+			}
+		}
+	}
+	if len(order) < len(dg) {
+		return nil, dg.cycleError(order)
+	}
+	return order, nil
+}
+
+// TopoLayers returns the vertices of the digraph in layers: the first layer
+// contains all vertices without successors, every other layer contains the
+// vertices whose successors are all in previous layers. Within a layer,
+// vertices are sorted.
+//
+// [Digraph.SortReverse] returns the vertices of these layers, with every layer
+// in reverse order. In contrast, if the digraph is not a DAG, a [CycleError]
+// is returned.
+func (dg Digraph[T]) TopoLayers() ([][]T, error) { return dg.TopoLayersFunc(cmp.Compare[T]) }
+
+// TopoLayersFunc returns the vertices of the digraph in layers, like
+// [Digraph.TopoLayers]. Within a layer, vertices are sorted according to the
+// given comparison function.
+func (dg Digraph[T]) TopoLayersFunc(cmpFn func(T, T) int) ([][]T, error) {
+	if len(dg) == 0 {
+		return nil, nil
+	}
+	rev := dg.Reverse()
+	outdegree := make(map[T]int, len(dg))
+	var layer []T
+	for v, closure := range dg {
+		if n := closure.Length(); n > 0 {
+			outdegree[v] = n
+		} else {
+			layer = append(layer, v)
+		}
+	}
+
+	var layers [][]T
+	count := 0
+	for len(layer) > 0 {
+		slices.SortFunc(layer, cmpFn)
+		layers = append(layers, layer)
+		count += len(layer)
+		var next []T
+		for _, v := range layer {
+			for pred := range rev[v].Values() {
+				outdegree[pred]--
+				if outdegree[pred] == 0 {
+					next = append(next, pred)
+				}
+			}
+		}
+		layer = next
+	}
+	if count < len(dg) {
+		return nil, dg.cycleError(slices.Concat(layers...))
+	}
+	return layers, nil
+}
+
+// cycleError returns a CycleError with a cycle of the digraph, that does not
+// contain any of the given vertices.
+func (dg Digraph[T]) cycleError(sorted []T) error {
+	done := set.New(sorted...)
+	var rest Digraph[T]
+	for vertex, closure := range dg {
+		if done.Contains(vertex) {
+			continue
+		}
+		rest = rest.AddVertex(vertex)
+		for next := range closure.Values() {
+			if !done.Contains(next) {
+				rest = rest.AddVertex(next).AddEdge(vertex, next)
+			}
+		}
+	}
+	return &CycleError[T]{Cycle: rest.FindCycle()}
+}
+
+// vertexHeap is a priority queue of vertices, ordered by a comparison
+// function.
+type vertexHeap[T any] struct {
+	vs  []T
+	cmp func(T, T) int
+}
+
+func (h vertexHeap[T]) Len() int           { return len(h.vs) }
+func (h vertexHeap[T]) Less(i, j int) bool { return h.cmp(h.vs[i], h.vs[j]) < 0 }
+func (h vertexHeap[T]) Swap(i, j int)      { h.vs[i], h.vs[j] = h.vs[j], h.vs[i] }
+func (h *vertexHeap[T]) Push(x any)        { h.vs = append(h.vs, x.(T)) }
+func (h *vertexHeap[T]) Pop() any {
+	n := len(h.vs)
+	x := h.vs[n-1]
+	h.vs = h.vs[:n-1]
+	return x
+}
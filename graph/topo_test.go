@@ -0,0 +1,132 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphTopoSort(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name  string
+		dg    graph.EdgeSlice[int]
+		exp   []int
+		cycle []int
+	}{
+		{"empty", nil, nil, nil},
+		{"single-edge", zps{{1, 2}}, []int{1, 2}, nil},
+		{"single-loop", zps{{1, 1}}, nil, []int{1}},
+		{"end-loop", zps{{1, 2}, {2, 2}}, nil, []int{2}},
+		{"sect-loop", zps{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {4, 2}}, nil, []int{2, 3, 4}},
+		{"two-islands", zps{{1, 2}, {2, 3}, {4, 5}}, []int{1, 2, 3, 4, 5}, nil},
+		{"direct-indirect", zps{{1, 2}, {1, 3}, {3, 2}}, []int{1, 3, 2}, nil},
+		{"diamond", zps{{4, 2}, {4, 3}, {2, 1}, {3, 1}}, []int{4, 2, 3, 1}, nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := createDigraph(tc.dg).TopoSort()
+			checkCycleError(t, err, tc.cycle)
+			if !slices.Equal(got, tc.exp) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestDigraphTopoLayers(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name  string
+		dg    graph.EdgeSlice[int]
+		exp   [][]int
+		cycle []int
+	}{
+		{"empty", nil, nil, nil},
+		{"single-edge", zps{{1, 2}}, [][]int{{2}, {1}}, nil},
+		{"single-loop", zps{{1, 1}}, nil, []int{1}},
+		{"long-loop", zps{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 2}}, nil, []int{2, 3, 4, 5}},
+		{"sect-loop", zps{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {4, 2}}, nil, []int{2, 3, 4}},
+		{"two-islands", zps{{1, 2}, {2, 3}, {4, 5}}, [][]int{{3, 5}, {2, 4}, {1}}, nil},
+		{"direct-indirect", zps{{1, 2}, {1, 3}, {3, 2}}, [][]int{{2}, {3}, {1}}, nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dg := createDigraph(tc.dg)
+			got, err := dg.TopoLayers()
+			checkCycleError(t, err, tc.cycle)
+			if !slices.EqualFunc(got, tc.exp, slices.Equal) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, got)
+			}
+			if err != nil {
+				return
+			}
+			var sr []int
+			for _, layer := range got {
+				for _, v := range slices.Backward(layer) {
+					sr = append(sr, v)
+				}
+			}
+			if exp := dg.SortReverse(); !slices.Equal(sr, exp) {
+				t.Errorf("layers %v differ from SortReverse %v", got, exp)
+			}
+		})
+	}
+}
+
+func TestDigraphTopoFunc(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 3}, {4, 5}, {6, 3}})
+	desc := func(a, b int) int { return cmp.Compare(b, a) }
+	got, err := dg.TopoSortFunc(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []int{6, 4, 5, 1, 2, 3}; !slices.Equal(got, exp) {
+		t.Errorf("expected %v, but got %v", exp, got)
+	}
+	layers, err := dg.TopoLayersFunc(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := [][]int{{5, 3}, {6, 4, 2}, {1}}; !slices.EqualFunc(layers, exp, slices.Equal) {
+		t.Errorf("expected %v, but got %v", exp, layers)
+	}
+}
+
+func checkCycleError(t *testing.T, err error, cycle []int) {
+	t.Helper()
+	if cycle == nil {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	var cerr *graph.CycleError[int]
+	if !errors.As(err, &cerr) {
+		t.Errorf("cycle error expected, but got %v", err)
+		return
+	}
+	if !errors.Is(err, graph.ErrCycle) {
+		t.Errorf("error should wrap ErrCycle: %v", err)
+	}
+	if !slices.Equal(cerr.Cycle, cycle) {
+		t.Errorf("cycle %v expected, but got %v", cycle, cerr.Cycle)
+	}
+}
@@ -0,0 +1,146 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"t73f.de/r/zero/set"
+)
+
+// TraversalOptions customize the traversals [Digraph.BFS], [Digraph.DFS], and
+// [Digraph.DFSPostOrder]. The zero value traverses all reachable vertices.
+type TraversalOptions[T cmp.Ordered] struct {
+	// MaxDepth limits the depth of visited vertices, if it is positive. The
+	// start vertex has depth zero.
+	MaxDepth int
+
+	// Visit is called for every visited vertex, before its successors are
+	// visited. If it returns false, the successors are not visited via this
+	// vertex, i.e. the subtree is pruned. The vertex itself is still returned
+	// by the traversal. May be nil.
+	Visit func(v T, depth int) bool
+}
+
+// descend returns true, if the successors of the given vertex should be
+// visited.
+func (opts *TraversalOptions[T]) descend(v T, depth int) bool {
+	if opts == nil {
+		return true
+	}
+	if opts.Visit != nil && !opts.Visit(v, depth) {
+		return false
+	}
+	return opts.MaxDepth <= 0 || depth < opts.MaxDepth
+}
+
+// BFS returns an iterator of all vertices reachable from the start vertex,
+// including itself, in breadth-first order, together with their depth, i.e.
+// their distance from the start vertex. Every vertex is visited at most once.
+// Successors are visited in sorted order, so the order is deterministic. The
+// options may be nil.
+func (dg Digraph[T]) BFS(start T, opts *TraversalOptions[T]) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		if !dg.HasVertex(start) {
+			return
+		}
+		type item struct {
+			vertex T
+			depth  int
+		}
+		visited := set.New(start)
+		queue := []item{{vertex: start}}
+		for len(queue) > 0 {
+			curr := queue[0]
+			queue = queue[1:]
+			descend := opts.descend(curr.vertex, curr.depth)
+			if !yield(curr.vertex, curr.depth) {
+				return
+			}
+			if !descend {
+				continue
+			}
+			for _, next := range slices.Sorted(dg[curr.vertex].Values()) {
+				if !visited.Contains(next) {
+					visited = visited.Add(next)
+					queue = append(queue, item{vertex: next, depth: curr.depth + 1})
+				}
+			}
+		}
+	}
+}
+
+// DFS returns an iterator of all vertices reachable from the start vertex,
+// including itself, in depth-first pre-order, together with their depth in
+// the depth-first search tree. Every vertex is visited at most once.
+// Successors are visited in sorted order, so the order is deterministic. The
+// options may be nil.
+func (dg Digraph[T]) DFS(start T, opts *TraversalOptions[T]) iter.Seq2[T, int] {
+	return dg.dfs(start, opts, false)
+}
+
+// DFSPostOrder works like [Digraph.DFS], but returns a vertex after all its
+// successors.
+func (dg Digraph[T]) DFSPostOrder(start T, opts *TraversalOptions[T]) iter.Seq2[T, int] {
+	return dg.dfs(start, opts, true)
+}
+
+func (dg Digraph[T]) dfs(start T, opts *TraversalOptions[T], post bool) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		if !dg.HasVertex(start) {
+			return
+		}
+		type frame struct {
+			vertex T
+			succs  []T
+			pos    int
+		}
+		var visited *set.Set[T]
+		enter := func(v T, depth int) (frame, bool) {
+			visited = visited.Add(v)
+			f := frame{vertex: v}
+			if opts.descend(v, depth) {
+				f.succs = slices.Sorted(dg[v].Values())
+			}
+			return f, post || yield(v, depth)
+		}
+
+		f, ok := enter(start, 0)
+		if !ok {
+			return
+		}
+		frames := []frame{f}
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			if top.pos < len(top.succs) {
+				next := top.succs[top.pos]
+				top.pos++
+				if !visited.Contains(next) {
+					if f, ok = enter(next, len(frames)); !ok {
+						return
+					}
+					frames = append(frames, f)
+				}
+				continue
+			}
+			v := top.vertex
+			frames = frames[:len(frames)-1]
+			if post && !yield(v, len(frames)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+func TestDigraphTraversal(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 3}, {1, 2}, {2, 4}, {3, 4}, {4, 5}, {2, 1}})
+	pruneTwo := &graph.TraversalOptions[int]{Visit: func(v, _ int) bool { return v != 2 }}
+	maxOne := &graph.TraversalOptions[int]{MaxDepth: 1}
+	testcases := []struct {
+		name  string
+		start int
+		opts  *graph.TraversalOptions[int]
+		bfs   string
+		dfs   string
+		post  string
+	}{
+		{"all", 1, nil, "1/0 2/1 3/1 4/2 5/3", "1/0 2/1 4/2 5/3 3/1", "5/3 4/2 2/1 3/1 1/0"},
+		{"inner", 4, nil, "4/0 5/1", "4/0 5/1", "5/1 4/0"},
+		{"missing", 7, nil, "", "", ""},
+		{"max-depth", 1, maxOne, "1/0 2/1 3/1", "1/0 2/1 3/1", "2/1 3/1 1/0"},
+		{"prune", 1, pruneTwo, "1/0 2/1 3/1 4/2 5/3", "1/0 2/1 3/1 4/2 5/3", "2/1 5/3 4/2 3/1 1/0"},
+		{"prune-start", 2, pruneTwo, "2/0", "2/0", "2/0"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatTraversal(dg.BFS(tc.start, tc.opts)); got != tc.bfs {
+				t.Errorf("BFS: expected %q, but got %q", tc.bfs, got)
+			}
+			if got := formatTraversal(dg.DFS(tc.start, tc.opts)); got != tc.dfs {
+				t.Errorf("DFS: expected %q, but got %q", tc.dfs, got)
+			}
+			if got := formatTraversal(dg.DFSPostOrder(tc.start, tc.opts)); got != tc.post {
+				t.Errorf("DFSPostOrder: expected %q, but got %q", tc.post, got)
+			}
+		})
+	}
+}
+
+func formatTraversal(seq iter.Seq2[int, int]) string {
+	var result []string
+	for v, depth := range seq {
+		result = append(result, fmt.Sprintf("%d/%d", v, depth))
+	}
+	return strings.Join(result, " ")
+}
+
+func TestDigraphTraversalVisit(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}})
+	var visited []int
+	opts := &graph.TraversalOptions[int]{Visit: func(v, _ int) bool {
+		visited = append(visited, v)
+		return true
+	}}
+	for range dg.DFSPostOrder(1, opts) {
+	}
+	if exp := []int{1, 2, 4, 3}; !slices.Equal(visited, exp) {
+		t.Errorf("expected visits %v, but got %v", exp, visited)
+	}
+}
+
+func TestDigraphTraversalBreak(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {1, 3}, {2, 4}, {3, 4}})
+	traversals := map[string]iter.Seq2[int, int]{
+		"BFS":          dg.BFS(1, nil),
+		"DFS":          dg.DFS(1, nil),
+		"DFSPostOrder": dg.DFSPostOrder(1, nil),
+	}
+	for name, seq := range traversals {
+		count := 0
+		for range seq {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		if count != 2 {
+			t.Errorf("%s: expected to stop after 2 vertices, but got %d", name, count)
+		}
+	}
+}
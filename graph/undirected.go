@@ -0,0 +1,237 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"t73f.de/r/zero/set"
+)
+
+// Graph relates orderable values in an undirected way. Every vertex is mapped
+// to the set of its neighbours. If `u` is a neighbour of `v`, then `v` is a
+// neighbour of `u`.
+type Graph[T cmp.Ordered] map[T]*set.Set[T]
+
+// AddVertex adds a vertex to the graph.
+func (g Graph[T]) AddVertex(v T) Graph[T] {
+	if g == nil {
+		return Graph[T]{v: nil}
+	}
+	if _, found := g[v]; !found {
+		g[v] = nil
+	}
+	return g
+}
+
+// RemoveVertex removes a vertex and all its edges from the graph.
+func (g Graph[T]) RemoveVertex(v T) {
+	for neighbour := range g[v].Values() {
+		g[neighbour] = g[neighbour].Remove(v)
+	}
+	delete(g, v)
+}
+
+// AddEdge adds a connection between `u` and `v`, in both directions. In
+// contrast to [Digraph.AddEdge] the vertices must not exist before.
+func (g Graph[T]) AddEdge(u, v T) Graph[T] {
+	if g == nil {
+		g = Graph[T]{}
+	}
+	g[u] = g[u].Add(v)
+	g[v] = g[v].Add(u)
+	return g
+}
+
+// HasVertex returns true, if `v` is a vertex of the graph.
+func (g Graph[T]) HasVertex(v T) bool {
+	_, found := g[v]
+	return found
+}
+
+// HasEdge returns true, if `u` and `v` are connected.
+func (g Graph[T]) HasEdge(u, v T) bool { return g[u].Contains(v) }
+
+// Equal returns true if both graphs have the same vertices and edges.
+func (g Graph[T]) Equal(other Graph[T]) bool {
+	return maps.EqualFunc(g, other, func(ng, no *set.Set[T]) bool { return ng.Equal(no) })
+}
+
+// Clone a graph.
+func (g Graph[T]) Clone() Graph[T] {
+	if len(g) == 0 {
+		return nil
+	}
+	copyG := make(Graph[T], len(g))
+	for vertex, neighbours := range g {
+		copyG[vertex] = neighbours.Clone()
+	}
+	return copyG
+}
+
+// Vertices returns the set of all vertices.
+func (g Graph[T]) Vertices() *set.Set[T] {
+	if len(g) == 0 {
+		return nil
+	}
+	verts := set.NewCap[T](len(g))
+	for vert := range g {
+		verts.Add(vert)
+	}
+	return verts
+}
+
+// Edges returns an unsorted slice of the edges of the graph. Every edge is
+// returned once, with From <= To.
+func (g Graph[T]) Edges() (es EdgeSlice[T]) {
+	for vert, neighbours := range g {
+		for next := range neighbours.Values() {
+			if vert <= next {
+				es = append(es, Edge[T]{From: vert, To: next})
+			}
+		}
+	}
+	return es
+}
+
+// Undirected returns the undirected graph with the same vertices and edges,
+// ignoring their direction.
+func (dg Digraph[T]) Undirected() (g Graph[T]) {
+	for vertex, closure := range dg {
+		g = g.AddVertex(vertex)
+		for next := range closure.Values() {
+			g = g.AddEdge(vertex, next)
+		}
+	}
+	return g
+}
+
+// ConnectedComponents returns the connected components of the graph. The
+// vertices of a component are sorted, and components are sorted by their
+// smallest vertex.
+func (g Graph[T]) ConnectedComponents() (comps [][]T) {
+	var seen *set.Set[T]
+	for _, root := range slices.Sorted(maps.Keys(g)) {
+		if seen.Contains(root) {
+			continue
+		}
+		seen = seen.Add(root)
+		comp := []T{root}
+		for pos := 0; pos < len(comp); pos++ {
+			for next := range g[comp[pos]].Values() {
+				if !seen.Contains(next) {
+					seen = seen.Add(next)
+					comp = append(comp, next)
+				}
+			}
+		}
+		slices.Sort(comp)
+		comps = append(comps, comp)
+	}
+	return comps
+}
+
+// Bipartition splits the vertices into two sets, so that every edge connects
+// vertices of different sets. If this is not possible, e.g. because of a
+// cycle with an odd number of edges, the result is false.
+//
+// Within each connected component, the smallest vertex is placed into the
+// first set.
+func (g Graph[T]) Bipartition() (*set.Set[T], *set.Set[T], bool) {
+	side := make(map[T]bool, len(g))
+	for _, comp := range g.ConnectedComponents() {
+		side[comp[0]] = false
+		queue := []T{comp[0]}
+		for len(queue) > 0 {
+			curr := queue[0]
+			queue = queue[1:]
+			for next := range g[curr].Values() {
+				s, found := side[next]
+				if !found {
+					side[next] = !side[curr]
+					queue = append(queue, next)
+				} else if s == side[curr] {
+					return nil, nil, false
+				}
+			}
+		}
+	}
+	var left, right *set.Set[T]
+	for v, s := range side {
+		if s {
+			right = right.Add(v)
+		} else {
+			left = left.Add(v)
+		}
+	}
+	return left, right, true
+}
+
+// SpanningForest returns a spanning tree for every connected component,
+// computed by a breadth-first search. Each tree is rooted at the smallest
+// vertex of its component, neighbours are visited in sorted order. The result
+// contains all vertices of the graph.
+func (g Graph[T]) SpanningForest() (forest Graph[T]) {
+	for _, comp := range g.ConnectedComponents() {
+		forest = forest.AddVertex(comp[0])
+		queue := []T{comp[0]}
+		for len(queue) > 0 {
+			curr := queue[0]
+			queue = queue[1:]
+			for _, next := range slices.Sorted(g[curr].Values()) {
+				if !forest.HasVertex(next) {
+					forest = forest.AddEdge(curr, next)
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	return forest
+}
+
+// GreedyColoring assigns a color to every vertex, so that connected vertices
+// have different colors. Colors are numbered from zero. Vertices are colored
+// in the order of decreasing degree (Welsh-Powell), then by value, each with
+// the smallest color not used by its neighbours.
+//
+// The number of colors is not necessarily minimal. A vertex with a self-loop
+// gets a color, although it is connected to itself.
+func (g Graph[T]) GreedyColoring() map[T]int {
+	if len(g) == 0 {
+		return nil
+	}
+	order := slices.SortedFunc(maps.Keys(g), func(u, v T) int {
+		if c := cmp.Compare(g[v].Length(), g[u].Length()); c != 0 {
+			return c
+		}
+		return cmp.Compare(u, v)
+	})
+	colors := make(map[T]int, len(g))
+	for _, v := range order {
+		var used *set.Set[int]
+		for next := range g[v].Values() {
+			if c, found := colors[next]; found {
+				used = used.Add(c)
+			}
+		}
+		c := 0
+		for used.Contains(c) {
+			c++
+		}
+		colors[v] = c
+	}
+	return colors
+}
@@ -0,0 +1,168 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"slices"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+	"t73f.de/r/zero/set"
+)
+
+func createGraph(pairs zps) (g graph.Graph[int]) {
+	for _, edge := range pairs {
+		g = g.AddEdge(edge.From, edge.To)
+	}
+	return g
+}
+
+func TestGraphEdges(t *testing.T) {
+	t.Parallel()
+	g := createGraph(zps{{2, 1}, {1, 2}, {3, 2}, {4, 4}}).AddVertex(5)
+	if exp := (zps{{1, 2}, {2, 3}, {4, 4}}); !g.Edges().Sort().Equal(exp) {
+		t.Errorf("expected %v, but got %v", exp, g.Edges().Sort())
+	}
+	if !g.HasEdge(2, 3) || !g.HasEdge(3, 2) || g.HasEdge(1, 3) {
+		t.Error("wrong edges", g)
+	}
+	if exp := set.New(1, 2, 3, 4, 5); !g.Vertices().Equal(exp) {
+		t.Errorf("expected vertices %v, but got %v", exp, g.Vertices())
+	}
+
+	g2 := g.Clone()
+	g2.RemoveVertex(2)
+	if exp := (zps{{4, 4}}); !g2.Edges().Sort().Equal(exp) || g2.HasVertex(2) {
+		t.Errorf("expected %v, but got %v", exp, g2.Edges())
+	}
+	if g.Equal(g2) || !g.Equal(g.Clone()) {
+		t.Error("clone must be independent")
+	}
+
+	dg := createDigraph(zps{{1, 2}, {3, 2}, {4, 4}}).AddVertex(5)
+	if !dg.Undirected().Equal(g) {
+		t.Errorf("expected %v, but got %v", g, dg.Undirected())
+	}
+}
+
+func TestGraphConnectedComponents(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		g    graph.EdgeSlice[int]
+		exp  [][]int
+	}{
+		{"empty", nil, nil},
+		{"single-edge", zps{{2, 1}}, [][]int{{1, 2}}},
+		{"single-loop", zps{{1, 1}}, [][]int{{1}}},
+		{"two-islands", zps{{5, 4}, {1, 2}, {3, 2}}, [][]int{{1, 2, 3}, {4, 5}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := createGraph(tc.g).ConnectedComponents()
+			if !slices.EqualFunc(got, tc.exp, slices.Equal) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestGraphBipartition(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name        string
+		g           graph.EdgeSlice[int]
+		left, right *set.Set[int]
+		ok          bool
+	}{
+		{"empty", nil, nil, nil, true},
+		{"single-edge", zps{{1, 2}}, set.New(1), set.New(2), true},
+		{"single-loop", zps{{1, 1}}, nil, nil, false},
+		{"square", zps{{1, 2}, {2, 3}, {3, 4}, {4, 1}}, set.New(1, 3), set.New(2, 4), true},
+		{"triangle", zps{{1, 2}, {2, 3}, {3, 1}}, nil, nil, false},
+		{"two-islands", zps{{1, 2}, {2, 3}, {5, 4}}, set.New(1, 3, 4), set.New(2, 5), true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			left, right, ok := createGraph(tc.g).Bipartition()
+			if ok != tc.ok || !left.Equal(tc.left) || !right.Equal(tc.right) {
+				t.Errorf("expected %v/%v/%v, but got %v/%v/%v", tc.left, tc.right, tc.ok, left, right, ok)
+			}
+		})
+	}
+}
+
+func TestGraphSpanningForest(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name string
+		g    graph.EdgeSlice[int]
+		exp  graph.EdgeSlice[int]
+	}{
+		{"empty", nil, nil},
+		{"single-loop", zps{{1, 1}}, nil},
+		{"triangle", zps{{1, 2}, {2, 3}, {3, 1}}, zps{{1, 2}, {1, 3}}},
+		{"square", zps{{1, 2}, {2, 3}, {3, 4}, {4, 1}}, zps{{1, 2}, {1, 4}, {2, 3}}},
+		{"two-islands", zps{{1, 2}, {2, 3}, {1, 3}, {5, 4}}, zps{{1, 2}, {1, 3}, {4, 5}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := createGraph(tc.g)
+			forest := g.SpanningForest()
+			if got := forest.Edges().Sort(); !got.Equal(tc.exp) {
+				t.Errorf("expected:\n%v, but got:\n%v", tc.exp, got)
+			}
+			if !forest.Vertices().Equal(g.Vertices()) {
+				t.Errorf("forest must contain all vertices %v, but got %v", g.Vertices(), forest.Vertices())
+			}
+		})
+	}
+}
+
+func TestGraphGreedyColoring(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name      string
+		g         graph.EdgeSlice[int]
+		numColors int
+	}{
+		{"empty", nil, 0},
+		{"single-edge", zps{{1, 2}}, 2},
+		{"triangle", zps{{1, 2}, {2, 3}, {3, 1}}, 3},
+		{"square", zps{{1, 2}, {2, 3}, {3, 4}, {4, 1}}, 2},
+		{"star", zps{{1, 2}, {1, 3}, {1, 4}, {1, 5}}, 2},
+		{"wheel", zps{{1, 2}, {1, 3}, {1, 4}, {1, 5}, {2, 3}, {3, 4}, {4, 5}, {5, 2}}, 3},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := createGraph(tc.g)
+			colors := g.GreedyColoring()
+			if len(colors) != len(g) {
+				t.Errorf("every vertex must be colored: %v", colors)
+			}
+			used := set.New[int]()
+			for _, edge := range g.Edges() {
+				if colors[edge.From] == colors[edge.To] {
+					t.Errorf("edge %v has same color %d", edge, colors[edge.From])
+				}
+			}
+			for _, c := range colors {
+				used.Add(c)
+			}
+			if used.Length() != tc.numColors {
+				t.Errorf("%d colors expected, but got %v", tc.numColors, colors)
+			}
+		})
+	}
+}
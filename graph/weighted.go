@@ -0,0 +1,291 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+
+	"t73f.de/r/zero/set"
+)
+
+// Weight is the constraint for edge weights. Unsigned types are not allowed,
+// because some algorithms must handle negative weights.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// WeightedDigraph is a digraph, where every edge has a weight, e.g. the
+// duration of a task.
+//
+// The zero value is an empty weighted digraph, ready to use.
+type WeightedDigraph[T cmp.Ordered, W Weight] struct {
+	dg      Digraph[T]
+	weights map[Edge[T]]W
+}
+
+// NewWeightedDigraph creates a weighted digraph with all vertices and edges
+// of the given digraph. The weight of each edge is calculated by the given
+// function.
+func NewWeightedDigraph[T cmp.Ordered, W Weight](dg Digraph[T], weight func(from, to T) W) *WeightedDigraph[T, W] {
+	wg := &WeightedDigraph[T, W]{}
+	for vertex := range dg {
+		wg.AddVertex(vertex)
+	}
+	for _, edge := range dg.Edges() {
+		wg.AddEdge(edge.From, edge.To, weight(edge.From, edge.To))
+	}
+	return wg
+}
+
+// AddVertex adds a vertex to the weighted digraph.
+func (wg *WeightedDigraph[T, W]) AddVertex(v T) *WeightedDigraph[T, W] {
+	wg.dg = wg.dg.AddVertex(v)
+	return wg
+}
+
+// AddEdge adds a connection from `from` to `to` with the given weight. In
+// contrast to [Digraph.AddEdge] the vertices must not exist before. If the
+// edge already exists, its weight is replaced.
+func (wg *WeightedDigraph[T, W]) AddEdge(from, to T, w W) *WeightedDigraph[T, W] {
+	wg.dg = wg.dg.AddVertex(from).AddVertex(to).AddEdge(from, to)
+	if wg.weights == nil {
+		wg.weights = map[Edge[T]]W{}
+	}
+	wg.weights[Edge[T]{From: from, To: to}] = w
+	return wg
+}
+
+// Weight returns the weight of the edge from `from` to `to`, and true if the
+// edge exists.
+func (wg *WeightedDigraph[T, W]) Weight(from, to T) (W, bool) {
+	w, found := wg.weights[Edge[T]{From: from, To: to}]
+	return w, found
+}
+
+// Digraph returns the underlying digraph, without weights. It must not be
+// modified.
+func (wg *WeightedDigraph[T, W]) Digraph() Digraph[T] { return wg.dg }
+
+// Errors returned by path algorithms.
+var (
+	// ErrNegativeWeight signals an edge with a negative weight, where only
+	// non-negative weights are allowed.
+	ErrNegativeWeight = errors.New("negative edge weight")
+
+	// ErrNegativeCycle signals a cycle with a negative total weight.
+	ErrNegativeCycle = errors.New("negative cycle")
+)
+
+// ShortestPath returns a path from `from` to `to` with the minimum number of
+// edges, together with the number of edges. It uses a breadth-first search,
+// successors are visited in sorted order.
+//
+// If there is no path, nil is returned.
+func (dg Digraph[T]) ShortestPath(from, to T) ([]T, int) {
+	if !dg.HasVertex(from) {
+		return nil, 0
+	}
+	prev := map[T]T{from: from}
+	queue := []T{from}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if curr == to {
+			path := buildPath(prev, from, to)
+			return path, len(path) - 1
+		}
+		for _, next := range slices.Sorted(dg[curr].Values()) {
+			if _, found := prev[next]; !found {
+				prev[next] = curr
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil, 0
+}
+
+// Dijkstra returns a path from `from` to `to` with minimum total weight,
+// together with that weight. All edge weights must be non-negative, otherwise
+// an error wrapping [ErrNegativeWeight] is returned.
+//
+// If there is no path, nil is returned.
+func (wg *WeightedDigraph[T, W]) Dijkstra(from, to T) ([]T, W, error) {
+	for edge, w := range wg.weights {
+		if w < 0 {
+			return nil, 0, fmt.Errorf("%w: %v->%v: %v", ErrNegativeWeight, edge.From, edge.To, w)
+		}
+	}
+	if !wg.dg.HasVertex(from) {
+		return nil, 0, nil
+	}
+
+	dist := map[T]W{from: 0}
+	prev := map[T]T{from: from}
+	var done *set.Set[T]
+	pq := &distQueue[T, W]{{vertex: from}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(distItem[T, W])
+		curr := item.vertex
+		if done.Contains(curr) {
+			continue
+		}
+		done = done.Add(curr)
+		if curr == to {
+			return buildPath(prev, from, to), item.dist, nil
+		}
+		for next := range wg.dg[curr].Values() {
+			if done.Contains(next) {
+				continue
+			}
+			d := item.dist + wg.weights[Edge[T]{From: curr, To: next}]
+			if old, found := dist[next]; !found || d < old || (d == old && curr < prev[next]) {
+				dist[next] = d
+				prev[next] = curr
+				heap.Push(pq, distItem[T, W]{vertex: next, dist: d})
+			}
+		}
+	}
+	return nil, 0, nil
+}
+
+// BellmanFord returns a path from `from` to `to` with minimum total weight,
+// together with that weight. Negative edge weights are allowed. If a cycle
+// with negative total weight is reachable from `from`, an error wrapping
+// [ErrNegativeCycle] is returned.
+//
+// If there is no path, nil is returned.
+func (wg *WeightedDigraph[T, W]) BellmanFord(from, to T) ([]T, W, error) {
+	if !wg.dg.HasVertex(from) {
+		return nil, 0, nil
+	}
+	edges := wg.dg.Edges().Sort()
+	dist := map[T]W{from: 0}
+	prev := map[T]T{from: from}
+	relax := func() (changed bool) {
+		for _, edge := range edges {
+			d, found := dist[edge.From]
+			if !found {
+				continue
+			}
+			d += wg.weights[edge]
+			if old, found := dist[edge.To]; !found || d < old {
+				dist[edge.To] = d
+				prev[edge.To] = edge.From
+				changed = true
+			}
+		}
+		return changed
+	}
+	for range len(wg.dg) - 1 {
+		if !relax() {
+			break
+		}
+	}
+	for _, edge := range edges {
+		if d, found := dist[edge.From]; found && d+wg.weights[edge] < dist[edge.To] {
+			return nil, 0, fmt.Errorf("%w: reachable via %v->%v", ErrNegativeCycle, edge.From, edge.To)
+		}
+	}
+
+	if d, found := dist[to]; found {
+		return buildPath(prev, from, to), d, nil
+	}
+	return nil, 0, nil
+}
+
+// LongestPath returns a path with maximum total weight, e.g. the critical
+// path through a graph of tasks, together with that weight. If there are
+// several such paths, the path ending at the smallest vertex is returned.
+//
+// The graph must be a DAG, otherwise a [CycleError] is returned.
+func (wg *WeightedDigraph[T, W]) LongestPath() ([]T, W, error) {
+	order, err := wg.dg.TopoSort()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(order) == 0 {
+		return nil, 0, nil
+	}
+	dist := make(map[T]W, len(order))
+	prev := make(map[T]T, len(order))
+	for _, v := range order {
+		prev[v] = v // Every vertex may start a path
+	}
+	for _, v := range order {
+		for _, next := range slices.Sorted(wg.dg[v].Values()) {
+			if d := dist[v] + wg.weights[Edge[T]{From: v, To: next}]; d > dist[next] {
+				dist[next] = d
+				prev[next] = v
+			}
+		}
+	}
+
+	last := slices.Min(order)
+	for _, v := range order {
+		if d := dist[v]; d > dist[last] || (d == dist[last] && v < last) {
+			last = v
+		}
+	}
+	var path []T
+	for v := last; ; v = prev[v] {
+		path = append(path, v)
+		if prev[v] == v {
+			break
+		}
+	}
+	slices.Reverse(path)
+	return path, dist[last], nil
+}
+
+// buildPath returns the path from `from` to `to`, given the map of
+// predecessors.
+func buildPath[T cmp.Ordered](prev map[T]T, from, to T) []T {
+	path := []T{to}
+	for v := to; v != from; {
+		v = prev[v]
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// distItem is an element of a distQueue.
+type distItem[T cmp.Ordered, W Weight] struct {
+	vertex T
+	dist   W
+}
+
+// distQueue is a priority queue of vertices, ordered by distance and vertex.
+type distQueue[T cmp.Ordered, W Weight] []distItem[T, W]
+
+func (q distQueue[T, W]) Len() int { return len(q) }
+func (q distQueue[T, W]) Less(i, j int) bool {
+	if q[i].dist != q[j].dist {
+		return q[i].dist < q[j].dist
+	}
+	return q[i].vertex < q[j].vertex
+}
+func (q distQueue[T, W]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *distQueue[T, W]) Push(x any)   { *q = append(*q, x.(distItem[T, W])) }
+func (q *distQueue[T, W]) Pop() any {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
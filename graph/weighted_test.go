@@ -0,0 +1,171 @@
+//-----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+//-----------------------------------------------------------------------------
+
+package graph_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"t73f.de/r/zero/graph"
+)
+
+type wedge struct {
+	from, to, w int
+}
+
+func createWeighted(edges []wedge) *graph.WeightedDigraph[int, int] {
+	var wg graph.WeightedDigraph[int, int]
+	for _, e := range edges {
+		wg.AddEdge(e.from, e.to, e.w)
+	}
+	return &wg
+}
+
+func TestDigraphShortestPath(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name     string
+		dg       graph.EdgeSlice[int]
+		from, to int
+		exp      []int
+	}{
+		{"empty", nil, 1, 2, nil},
+		{"self", zps{{1, 2}}, 1, 1, []int{1}},
+		{"single-edge", zps{{1, 2}}, 1, 2, []int{1, 2}},
+		{"reverse", zps{{1, 2}}, 2, 1, nil},
+		{"shortcut", zps{{1, 2}, {2, 3}, {3, 4}, {1, 3}}, 1, 4, []int{1, 3, 4}},
+		{"sorted", zps{{1, 3}, {1, 2}, {2, 4}, {3, 4}}, 1, 4, []int{1, 2, 4}},
+		{"loop", zps{{1, 2}, {2, 1}, {2, 3}}, 1, 3, []int{1, 2, 3}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, n := createDigraph(tc.dg).ShortestPath(tc.from, tc.to)
+			if !slices.Equal(got, tc.exp) {
+				t.Errorf("expected %v, but got %v", tc.exp, got)
+			}
+			if exp := max(len(tc.exp)-1, 0); n != exp {
+				t.Errorf("expected length %d, but got %d", exp, n)
+			}
+		})
+	}
+}
+
+func TestWeightedShortestPath(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name     string
+		edges    []wedge
+		from, to int
+		exp      []int
+		weight   int
+	}{
+		{"empty", nil, 1, 2, nil, 0},
+		{"self", []wedge{{1, 2, 3}}, 1, 1, []int{1}, 0},
+		{"single-edge", []wedge{{1, 2, 3}}, 1, 2, []int{1, 2}, 3},
+		{"unreachable", []wedge{{1, 2, 3}, {3, 4, 1}}, 1, 4, nil, 0},
+		{"detour", []wedge{{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {1, 4, 5}}, 1, 4, []int{1, 2, 3, 4}, 3},
+		{"direct", []wedge{{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {1, 4, 2}}, 1, 4, []int{1, 4}, 2},
+		{"tie", []wedge{{1, 3, 1}, {1, 2, 1}, {2, 4, 1}, {3, 4, 1}}, 1, 4, []int{1, 2, 4}, 2},
+		{"loop", []wedge{{1, 2, 1}, {2, 1, 1}, {2, 3, 4}}, 1, 3, []int{1, 2, 3}, 5},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			wg := createWeighted(tc.edges)
+			for name, fn := range map[string]func(int, int) ([]int, int, error){
+				"Dijkstra":    wg.Dijkstra,
+				"BellmanFord": wg.BellmanFord,
+			} {
+				got, w, err := fn(tc.from, tc.to)
+				if err != nil {
+					t.Errorf("%s: unexpected error %v", name, err)
+					continue
+				}
+				if !slices.Equal(got, tc.exp) || w != tc.weight {
+					t.Errorf("%s: expected %v/%d, but got %v/%d", name, tc.exp, tc.weight, got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedNegative(t *testing.T) {
+	t.Parallel()
+	wg := createWeighted([]wedge{{1, 2, 4}, {1, 3, 5}, {3, 2, -3}, {2, 4, 1}})
+	if _, _, err := wg.Dijkstra(1, 4); !errors.Is(err, graph.ErrNegativeWeight) {
+		t.Errorf("ErrNegativeWeight expected, but got %v", err)
+	}
+	got, w, err := wg.BellmanFord(1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []int{1, 3, 2, 4}; !slices.Equal(got, exp) || w != 3 {
+		t.Errorf("expected %v/3, but got %v/%d", exp, got, w)
+	}
+
+	wg.AddEdge(2, 3, 2)
+	if _, _, err = wg.BellmanFord(1, 4); !errors.Is(err, graph.ErrNegativeCycle) {
+		t.Errorf("ErrNegativeCycle expected, but got %v", err)
+	}
+	if _, _, err = wg.BellmanFord(4, 4); err != nil {
+		t.Errorf("unreachable negative cycle must be ignored, but got %v", err)
+	}
+}
+
+func TestWeightedLongestPath(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name   string
+		edges  []wedge
+		exp    []int
+		weight int
+	}{
+		{"empty", nil, nil, 0},
+		{"single-edge", []wedge{{1, 2, 3}}, []int{1, 2}, 3},
+		{"critical", []wedge{{1, 2, 3}, {1, 3, 1}, {2, 4, 2}, {3, 4, 5}, {4, 5, 1}}, []int{1, 3, 4, 5}, 7},
+		{"negative-start", []wedge{{1, 2, -5}, {2, 3, 2}}, []int{2, 3}, 2},
+		{"tie", []wedge{{1, 2, 2}, {3, 4, 2}}, []int{1, 2}, 2},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, w, err := createWeighted(tc.edges).LongestPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.exp) || w != tc.weight {
+				t.Errorf("expected %v/%d, but got %v/%d", tc.exp, tc.weight, got, w)
+			}
+		})
+	}
+
+	wg := createWeighted([]wedge{{1, 2, 1}, {2, 1, 1}})
+	if _, _, err := wg.LongestPath(); !errors.Is(err, graph.ErrCycle) {
+		t.Errorf("ErrCycle expected, but got %v", err)
+	}
+}
+
+func TestNewWeightedDigraph(t *testing.T) {
+	t.Parallel()
+	dg := createDigraph(zps{{1, 2}, {2, 3}}).AddVertex(7)
+	wg := graph.NewWeightedDigraph(dg, func(from, to int) float64 { return float64(from*10 + to) })
+	if !wg.Digraph().Equal(dg) {
+		t.Errorf("expected digraph %v, but got %v", dg, wg.Digraph())
+	}
+	if w, found := wg.Weight(2, 3); !found || w != 23 {
+		t.Errorf("weight 23 expected, but got %v/%v", w, found)
+	}
+	if _, found := wg.Weight(3, 2); found {
+		t.Error("edge 3->2 must not have a weight")
+	}
+}
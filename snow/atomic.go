@@ -0,0 +1,98 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// AtomicGenerator is a generator for unique keys, that does not use a lock.
+// Timestamp and sequence number of the last created key are packed into one
+// word, which is updated by an atomic compare-and-swap operation. Under heavy
+// parallel load, it scales better than a [Generator].
+//
+// All keys are unique, and keys with the same application value are
+// increasing. In contrast to a [Generator], it never waits. If the clock moves
+// backwards, the last timestamp is used further. If the sequence numbers of a
+// timestamp are exhausted, the next timestamp is used, as with [PolicyBorrow].
+//
+// The zero value is a generator without application defined bits, that uses
+// the system clock.
+type AtomicGenerator struct {
+	state  atomic.Uint64 // timestamp << SeqBits | sequence number of last key
+	layout Layout        // Bit layout of the created keys
+	clock  Clock         // Source of the current time; nil means system clock
+}
+
+// NewAtomic creates a new lock-free key generator with the given layout and
+// clock. If clock is nil, the system clock is used.
+func NewAtomic(layout Layout, clock Clock) *AtomicGenerator {
+	return &AtomicGenerator{layout: layout, clock: clock}
+}
+
+// Create generates a new key with the given application data.
+//
+// It panics, if [AtomicGenerator.CreateE] returns an error.
+func (gen *AtomicGenerator) Create(appID uint) Key {
+	key, err := gen.CreateE(appID)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// CreateE generates a new key with the given application data, or returns
+// an error.
+func (gen *AtomicGenerator) CreateE(appID uint) (Key, error) {
+	layout := gen.layout
+	if maxID := layout.MaxAppID(); appID > maxID {
+		return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrAppID, appID, maxID)
+	}
+	clock := gen.clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	ts := layout.timestamp(clock.Now())
+	if ts < 0 {
+		return Invalid, fmt.Errorf("%w: %v (min: 0)", ErrTimestamp, ts)
+	}
+	seqBits := layout.SeqBits()
+	maxSeq := uint64(layout.MaxSeq())
+	maxTS := layout.maxTimestamp()
+	for {
+		last := gen.state.Load()
+		lastTS, lastSeq := int64(last>>seqBits), last&maxSeq
+		nextTS, nextSeq := ts, uint64(0)
+		if ts <= lastTS {
+			// Same timestamp, or clock moved backwards
+			nextTS, nextSeq = lastTS, lastSeq+1
+			if lastSeq == maxSeq {
+				nextTS, nextSeq = lastTS+1, 0
+			}
+		}
+		if nextTS > maxTS {
+			return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrTimestamp, nextTS, maxTS)
+		}
+		if gen.state.CompareAndSwap(last, uint64(nextTS)<<seqBits|nextSeq) {
+			return layout.key(nextTS, appID, nextSeq), nil
+		}
+	}
+}
+
+// Layout returns the layout of the keys created by the generator.
+func (gen *AtomicGenerator) Layout() Layout { return gen.layout }
+
+// MaxAppID returns the maximum application value of the generator.
+func (gen *AtomicGenerator) MaxAppID() uint { return gen.layout.MaxAppID() }
@@ -0,0 +1,107 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestAtomicGeneratorParallel(t *testing.T) {
+	t.Parallel()
+	const numWorkers, numKeys = 8, 10000
+	generator := snow.NewAtomic(snow.NewLayout(3), nil)
+	results := make([][]snow.Key, numWorkers)
+	var wg sync.WaitGroup
+	for w := range numWorkers {
+		wg.Go(func() {
+			keys := make([]snow.Key, numKeys)
+			for i := range keys {
+				keys[i] = generator.Create(uint(w))
+			}
+			results[w] = keys
+		})
+	}
+	wg.Wait()
+
+	seen := make(map[snow.Key]struct{}, numWorkers*numKeys)
+	for w, keys := range results {
+		for i, key := range keys {
+			if i > 0 && key <= keys[i-1] {
+				t.Errorf("worker %d: key %v is not greater than %v", w, key, keys[i-1])
+			}
+			if got := generator.Layout().AppID(key); got != uint(w) {
+				t.Errorf("worker %d: key %v has application value %d", w, key, got)
+			}
+			if _, found := seen[key]; found {
+				t.Errorf("key %v created twice", key)
+			}
+			seen[key] = struct{}{}
+		}
+	}
+}
+
+func TestAtomicGeneratorBorrow(t *testing.T) {
+	t.Parallel()
+	clock := newTestClock()
+	layout, err := snow.NewLayoutWith(snow.LayoutConfig{AppBits: 64 - 42 - 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generator := snow.NewAtomic(layout, clock)
+	start := clock.now
+	var keys []snow.Key
+	for range 6 {
+		keys = append(keys, generator.Create(1))
+	}
+	clock.now = clock.now.Add(-time.Second)
+	keys = append(keys, generator.Create(1))
+	for i, key := range keys {
+		if i > 0 && key <= keys[i-1] {
+			t.Errorf("key %v is not greater than %v", key, keys[i-1])
+		}
+		if exp := start.Add(time.Duration(i/4) * time.Millisecond); !layout.Time(key).Equal(exp) {
+			t.Errorf("key %d: time %v expected, but got %v", i, exp, layout.Time(key))
+		}
+	}
+	if clock.slept != 0 {
+		t.Errorf("generator should not sleep, but slept %v", clock.slept)
+	}
+}
+
+func TestAtomicGeneratorErrors(t *testing.T) {
+	t.Parallel()
+	var zero snow.AtomicGenerator
+	if _, err := zero.CreateE(1); !errors.Is(err, snow.ErrAppID) {
+		t.Errorf("ErrAppID expected, but got %v", err)
+	}
+	if key := zero.Create(0); key == snow.Invalid {
+		t.Error("zero value generator should create a valid key")
+	}
+
+	clock := newTestClock()
+	clock.now = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	generator := snow.NewAtomic(snow.NewLayout(0), clock)
+	if _, err := generator.CreateE(0); !errors.Is(err, snow.ErrTimestamp) {
+		t.Errorf("ErrTimestamp expected, but got %v", err)
+	}
+	clock.now = snow.NewLayout(0).MaxTime()
+	if _, err := generator.CreateE(0); err != nil {
+		t.Errorf("key at maximum time expected, but got %v", err)
+	}
+}
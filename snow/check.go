@@ -0,0 +1,93 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+// checkChars contains all check symbols, as defined by Douglas Crockford.
+// The first 32 symbols are the base-32 digits.
+const checkChars = base32chars + "*~$=U"
+
+// CheckSymbol returns the check symbol of the key. It is the key value modulo
+// 37, encoded as one of the base-32 digits or one of the symbols "*~$=U".
+func (key Key) CheckSymbol() byte { return checkChars[uint64(key)%37] }
+
+// FormatChecked returns the same string as [Key.Format], but with an
+// additional check symbol at the end. This allows to detect a single mistyped
+// character, or two adjacent characters that were swapped.
+//
+// For example: Invalid.FormatChecked(4, "-") == "0-0000-0000-00000".
+func (key Key) FormatChecked(groupSize int, sep string) string {
+	return key.Format(groupSize, sep) + string(key.CheckSymbol())
+}
+
+// ParseChecked parses a string into a key, where the last character is the
+// check symbol of the key, as produced by [Key.FormatChecked]. Lower case
+// letters are accepted, as with [Parse].
+//
+// If the check symbol does not match the key, a [ParseError] with cause
+// [ErrChecksum] is returned.
+func ParseChecked(s string) (Key, error) {
+	if s == "" {
+		return Invalid, ErrEmptyKey
+	}
+	pos := len(s) - 1
+	sym := decodeCheckSymbol(s[pos])
+	if sym < 0 {
+		return Invalid, &ParseError{Input: s, Pos: pos, Err: ErrBadChar}
+	}
+	if pos == 0 {
+		return Invalid, ErrEmptyKey
+	}
+	key, err := Parse(s[:pos])
+	if err != nil {
+		if pe, isParseError := err.(*ParseError); isParseError {
+			pe.Input = s
+		}
+		return Invalid, err
+	}
+	if uint64(key)%37 != uint64(sym) {
+		return Invalid, &ParseError{Input: s, Pos: pos, Err: ErrChecksum}
+	}
+	return key, nil
+}
+
+// MustParseChecked parses a checked string into a key, and panics if that is
+// not possible.
+func MustParseChecked(s string) Key {
+	key, err := ParseChecked(s)
+	if err == nil {
+		return key
+	}
+	panic(err)
+}
+
+func decodeCheckSymbol(ch byte) int8 {
+	switch ch {
+	case '*':
+		return 32
+	case '~':
+		return 33
+	case '$':
+		return 34
+	case '=':
+		return 35
+	case 'U', 'u':
+		return 36
+	}
+	if '0' <= ch && ch < 128 {
+		if val := decode32map[ch-'0']; 0 <= val && val <= 31 {
+			return val
+		}
+	}
+	return -1
+}
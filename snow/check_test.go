@@ -0,0 +1,141 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestKeyFormatChecked(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		key snow.Key
+		exp string
+	}{
+		{snow.Invalid, "0-0000-0000-00000"},
+		{1, "0-0000-0000-00011"},
+		{32, "0-0000-0000-0010*"},
+		{36, "0-0000-0000-0014U"},
+		{35, "0-0000-0000-0013="},
+		{34, "0-0000-0000-0012$"},
+		{33, "0-0000-0000-0011~"},
+		{37, "0-0000-0000-00150"},
+		{math.MaxUint64, "F-ZZZZ-ZZZZ-ZZZZB"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.exp, func(t *testing.T) {
+			got := tc.key.FormatChecked(4, "-")
+			if got != tc.exp {
+				t.Errorf("%q expected, but got %q", tc.exp, got)
+			}
+			key, err := snow.ParseChecked(got)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if key != tc.key {
+				t.Errorf("key %v expected, but got %v", tc.key, key)
+			}
+		})
+	}
+}
+
+func TestParseChecked(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		s   string
+		err error
+		exp snow.Key
+	}{
+		{"", snow.ErrEmptyKey, 0},
+		{"0", snow.ErrEmptyKey, 0},
+		{"00", nil, 0},
+		{"14u", nil, 36},
+		{"1O*", nil, 32},
+		{"il-om", nil, 1056},
+		{"1#", snow.ErrBadChar, 0},
+		{"1<1", snow.ErrBadChar, 0},
+		{"1*1", snow.ErrBadChar, 0},
+		{"-01", snow.ErrBadChar, 0},
+		{"12", snow.ErrChecksum, 0},
+		{"0-0000-0000-00012", snow.ErrChecksum, 0},
+		{"0-0000-0000-01001", snow.ErrChecksum, 0},
+		{"1ZZZZZZZZZZZZZ0", snow.ErrOverflow, 0},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.s, func(t *testing.T) {
+			got, err := snow.ParseChecked(tc.s)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("error %v expected, but got %v", tc.err, err)
+				return
+			}
+			if err != nil {
+				if tc.err != snow.ErrEmptyKey {
+					var pe *snow.ParseError
+					if !errors.As(err, &pe) {
+						t.Errorf("ParseError expected, but got %T", err)
+					} else if pe.Input != tc.s {
+						t.Errorf("input %q expected, but got %q", tc.s, pe.Input)
+					}
+				}
+				return
+			}
+			if got != tc.exp {
+				t.Errorf("key %v expected, but got %v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestParseCheckedTypo(t *testing.T) {
+	t.Parallel()
+	const digits = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	rnd := rand.New(rand.NewSource(4711))
+	for range 1000 {
+		key := snow.Key(rnd.Uint64())
+		s := []byte(key.FormatChecked(13, ""))
+
+		pos := rnd.Intn(len(s) - 1)
+		orig := s[pos]
+		for s[pos] == orig {
+			s[pos] = digits[rnd.Intn(len(digits))]
+		}
+		if got, err := snow.ParseChecked(string(s)); err == nil {
+			t.Errorf("typo in %q not detected: %v", s, got)
+		}
+		s[pos] = orig
+
+		if s[pos] != s[pos+1] && pos+1 < len(s)-1 {
+			s[pos], s[pos+1] = s[pos+1], s[pos]
+			if got, err := snow.ParseChecked(string(s)); err == nil {
+				t.Errorf("transposition in %q not detected: %v", s, got)
+			}
+		}
+	}
+}
+
+func TestParseErrorCause(t *testing.T) {
+	t.Parallel()
+	if _, err := snow.Parse("0<"); !errors.Is(err, snow.ErrBadChar) {
+		t.Errorf("error %v expected, but got %v", snow.ErrBadChar, err)
+	}
+	if _, err := snow.Parse("1ZZZZZZZZZZZZZ"); !errors.Is(err, snow.ErrOverflow) {
+		t.Errorf("error %v expected, but got %v", snow.ErrOverflow, err)
+	}
+}
@@ -0,0 +1,280 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+// ----- UUIDv7
+
+// UUID is a 128 bit universally unique identifier, as specified in RFC 9562.
+type UUID [16]byte
+
+// ErrUUID signals an UUID that does not contain a [Key].
+var ErrUUID = errors.New("UUID does not contain a key")
+
+// UUID returns a version 7 UUID that contains the key. The timestamp of the
+// UUID is the time of the key in milliseconds since the Unix epoch, as
+// returned by [Key.Time]. The remaining 22 bits of the key are stored in the
+// following bits of the UUID, all other bits are zero.
+//
+// The order of keys is retained by their UUID representation.
+//
+// The key is interpreted with the default layout. Use [Layout.UUID] for keys
+// of other layouts, otherwise the timestamp of the UUID is wrong.
+func (key Key) UUID() UUID {
+	u, _ := Layout{}.UUID(key) // Default layout fits always
+	return u
+}
+
+// KeyFromUUID returns the key contained in the given UUID. An error wrapping
+// [ErrUUID] is returned, if the UUID was not produced by [Key.UUID].
+//
+// The key is built with the default layout. Use [Layout.KeyFromUUID] for
+// other layouts.
+func KeyFromUUID(u UUID) (Key, error) { return Layout{}.KeyFromUUID(u) }
+
+// UUID returns a version 7 UUID that contains the key of this layout. The
+// timestamp of the UUID is the time of the key in milliseconds since the Unix
+// epoch, as returned by [Layout.Time]. The application value and the sequence
+// number are stored in the following bits of the UUID, all other bits are
+// zero.
+//
+// An error wrapping [ErrTimestamp] is returned, if the time of the key is
+// before the Unix epoch, or does not fit in the 48 bits of the UUID timestamp.
+func (l Layout) UUID(key Key) (UUID, error) {
+	var u UUID
+	ms := l.Time(key).UnixMilli()
+	if ms < 0 || ms >= 1<<48 {
+		return u, fmt.Errorf("%w: %v is not a UUID timestamp", ErrTimestamp, ms)
+	}
+	random := uint64(key & l.randomMask())
+	// 48 bit timestamp, 4 bit version, 12 bit rand_a, 2 bit variant, 62 bit rand_b.
+	// The random bits are stored left aligned in rand_a and rand_b.
+	var randA, randB uint64
+	if shift := uuidRandomBits - l.randomBits(); shift >= 62 {
+		randA = random << (shift - 62)
+	} else {
+		randA = random >> (62 - shift)
+		randB = random << shift & (1<<62 - 1)
+	}
+	binary.BigEndian.PutUint64(u[:8], uint64(ms)<<16|0x7000|randA)
+	binary.BigEndian.PutUint64(u[8:], uint64(0b10)<<62|randB)
+	return u, nil
+}
+
+// uuidRandomBits is the number of bits of rand_a and rand_b of an UUIDv7.
+const uuidRandomBits = 12 + 62
+
+// KeyFromUUID returns the key of this layout contained in the given UUID. An
+// error wrapping [ErrUUID] is returned, if the UUID was not produced by
+// [Layout.UUID] of this layout.
+func (l Layout) KeyFromUUID(u UUID) (Key, error) {
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+	if hi&0xf000 != 0x7000 {
+		return Invalid, fmt.Errorf("%w: version %d", ErrUUID, hi>>12&0xf)
+	}
+	randA, randB := hi&0xfff, lo&(1<<62-1)
+	var random uint64
+	unused := false
+	if shift := uuidRandomBits - l.randomBits(); shift >= 62 {
+		random = randA >> (shift - 62)
+		unused = randB != 0 || randA&(1<<(shift-62)-1) != 0
+	} else {
+		random = randA<<(62-shift) | randB>>shift
+		unused = randB&(1<<shift-1) != 0
+	}
+	if lo>>62 != 0b10 || unused {
+		return Invalid, fmt.Errorf("%w: variant or unused bits", ErrUUID)
+	}
+	ms := int64(hi >> 16)
+	ts := l.timestamp(time.UnixMilli(ms))
+	if ts < 0 || ts > l.maxTimestamp() || !l.timeOf(ts).Equal(time.UnixMilli(ms)) {
+		return Invalid, fmt.Errorf("%w: timestamp %d", ErrUUID, ms)
+	}
+	return l.key(ts, 0, 0) | Key(random), nil
+}
+
+// String returns the canonical representation of the UUID, e.g.
+// "018fd118-9400-7000-8000-000000000000" for the UUID of [Invalid].
+func (u UUID) String() string {
+	var result [36]byte
+	pos := 0
+	for i, b := range u {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			result[pos] = '-'
+			pos++
+		}
+		result[pos] = hexChars[b>>4]
+		result[pos+1] = hexChars[b&0xf]
+		pos += 2
+	}
+	return string(result[:])
+}
+
+// FormatUUID returns the canonical string representation of the UUID of the
+// key, see [Key.UUID].
+func (key Key) FormatUUID() string { return key.UUID().String() }
+
+// ParseUUID parses the canonical string representation of an UUID, and
+// returns the key contained in the UUID, see [KeyFromUUID].
+func ParseUUID(s string) (Key, error) { return Layout{}.ParseUUID(s) }
+
+// ParseUUID parses the canonical string representation of an UUID, and
+// returns the key of this layout contained in the UUID, see
+// [Layout.KeyFromUUID].
+func (l Layout) ParseUUID(s string) (Key, error) {
+	if s == "" {
+		return Invalid, ErrEmptyKey
+	}
+	if len(s) != uuidLen {
+		return Invalid, &ParseError{Input: s, Pos: min(len(s), uuidLen), Err: ErrLength, Encoding: "UUID"}
+	}
+	var u UUID
+	upos := 0
+	for i := 0; i < len(s); i++ {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if s[i] != '-' {
+				return Invalid, &ParseError{Input: s, Pos: i, Err: ErrBadChar, Encoding: "UUID"}
+			}
+			continue
+		}
+		hi, lo := decodeHex(s[i]), decodeHex(s[i+1])
+		if hi < 0 {
+			return Invalid, &ParseError{Input: s, Pos: i, Err: ErrBadChar, Encoding: "UUID"}
+		}
+		if lo < 0 {
+			return Invalid, &ParseError{Input: s, Pos: i + 1, Err: ErrBadChar, Encoding: "UUID"}
+		}
+		u[upos] = byte(hi<<4 | lo)
+		upos++
+		i++
+	}
+	return l.KeyFromUUID(u)
+}
+
+// uuidLen is the length of the canonical string representation of an UUID.
+const uuidLen = 36
+
+// ----- Hexadecimal
+
+const hexChars = "0123456789abcdef"
+
+// FormatHex returns the key as a string of exactly 16 lower case hexadecimal
+// digits. The order of keys is retained by this representation.
+func (key Key) FormatHex() string {
+	var result [16]byte
+	u64 := uint64(key)
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = hexChars[u64&0xf]
+		u64 >>= 4
+	}
+	return string(result[:])
+}
+
+// ParseHex parses a string of hexadecimal digits into a key. Both lower and
+// upper case digits are accepted.
+func ParseHex(s string) (Key, error) {
+	if s == "" {
+		return Invalid, ErrEmptyKey
+	}
+	result := Key(0)
+	for i := range len(s) {
+		val := decodeHex(s[i])
+		if val < 0 {
+			return Invalid, &ParseError{Input: s, Pos: i, Err: ErrBadChar, Encoding: "hex"}
+		}
+		if result&0xF000000000000000 != 0 {
+			return Invalid, &ParseError{Input: s, Pos: i, Err: ErrOverflow, Encoding: "hex"}
+		}
+		result = result<<4 | Key(val)
+	}
+	return result, nil
+}
+
+func decodeHex(ch byte) int {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return int(ch - '0')
+	case 'a' <= ch && ch <= 'f':
+		return int(ch-'a') + 10
+	case 'A' <= ch && ch <= 'F':
+		return int(ch-'A') + 10
+	}
+	return -1
+}
+
+// ----- Base-58
+
+// base58Chars is the alphabet used by Bitcoin. It is sorted in ASCII order.
+const base58Chars = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Len is the number of base-58 digits of the largest key.
+const base58Len = 11
+
+// FormatBase58 returns the key as a string of exactly 11 base-58 digits,
+// using the Bitcoin alphabet. Leading zeroes are represented by the digit
+// '1'. The order of keys is retained by this representation.
+func (key Key) FormatBase58() string {
+	var result [base58Len]byte
+	u64 := uint64(key)
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = base58Chars[u64%58]
+		u64 /= 58
+	}
+	return string(result[:])
+}
+
+// ParseBase58 parses a string of base-58 digits of the Bitcoin alphabet into
+// a key.
+func ParseBase58(s string) (Key, error) {
+	if s == "" {
+		return Invalid, ErrEmptyKey
+	}
+	var result uint64
+	for i := range len(s) {
+		val := decodeBase58(s[i])
+		if val < 0 {
+			return Invalid, &ParseError{Input: s, Pos: i, Err: ErrBadChar, Encoding: "base-58"}
+		}
+		hi, lo := bits.Mul64(result, 58)
+		lo, carry := bits.Add64(lo, uint64(val), 0)
+		if hi != 0 || carry != 0 {
+			return Invalid, &ParseError{Input: s, Pos: i, Err: ErrOverflow, Encoding: "base-58"}
+		}
+		result = lo
+	}
+	return Key(result), nil
+}
+
+var decode58map = [...]int8{
+	-1, 0, 1, 2, 3, 4, 5, 6, 7, 8, -1, -1, -1, -1, -1, -1, // 0x30 .. 0x3f
+	-1, 9, 10, 11, 12, 13, 14, 15, 16, -1, 17, 18, 19, 20, 21, -1, // 0x40 .. 0x4f
+	22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, -1, -1, -1, -1, -1, // 0x50 .. 0x5f
+	-1, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, -1, 44, 45, 46, // 0x60 .. 0x6f
+	47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, -1, -1, -1, -1, -1, // 0x70 .. 0x7f
+}
+
+func decodeBase58(ch byte) int {
+	if '0' <= ch && ch < 128 {
+		return int(decode58map[ch-'0'])
+	}
+	return -1
+}
@@ -0,0 +1,227 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+// encodings lists all encodings of a key, which retain the order of keys.
+var encodings = []struct {
+	name   string
+	format func(snow.Key) string
+	parse  func(string) (snow.Key, error)
+}{
+	{"base32", func(key snow.Key) string { return key.Format(13, "") }, snow.Parse},
+	{"uuid", snow.Key.FormatUUID, snow.ParseUUID},
+	{"hex", snow.Key.FormatHex, snow.ParseHex},
+	{"base58", snow.Key.FormatBase58, snow.ParseBase58},
+}
+
+func TestEncodingsRoundTrip(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(4711))
+	keys := []snow.Key{0, 1, 0x3fffff, 0x400000, math.MaxUint64 - 1, math.MaxUint64}
+	for range 10000 {
+		keys = append(keys, snow.Key(rnd.Uint64()))
+	}
+	for _, enc := range encodings {
+		t.Run(enc.name, func(t *testing.T) {
+			length := len(enc.format(0))
+			for _, key := range keys {
+				s := enc.format(key)
+				if len(s) != length {
+					t.Errorf("encoding of %v should have length %d, but got %q", key, length, s)
+				}
+				got, err := enc.parse(s)
+				if err != nil {
+					t.Errorf("unable to parse %q: %v", s, err)
+					continue
+				}
+				if got != key {
+					t.Errorf("key %v was encoded as %q, but parsed as %v", key, s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodingsOrder(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(4712))
+	for _, enc := range encodings {
+		t.Run(enc.name, func(t *testing.T) {
+			for range 10000 {
+				k1 := snow.Key(rnd.Uint64())
+				k2 := k1 + snow.Key(rnd.Int63n(1<<(rnd.Intn(62)+1)))
+				if rnd.Intn(2) == 0 {
+					k1, k2 = k2, k1
+				}
+				s1, s2 := enc.format(k1), enc.format(k2)
+				if exp, got := cmp.Compare(k1, k2), strings.Compare(s1, s2); exp != got {
+					t.Errorf("%v <=> %v is %d, but %q <=> %q is %d", k1, k2, exp, s1, s2, got)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyUUID(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		key snow.Key
+		exp string
+	}{
+		{snow.Invalid, "018fd118-9400-7000-8000-000000000000"},
+		{0x3fffff, "018fd118-9400-7fff-bff0-000000000000"},
+		{0x400001, "018fd118-9401-7000-8010-000000000000"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.exp, func(t *testing.T) {
+			if got := tc.key.FormatUUID(); got != tc.exp {
+				t.Errorf("%q expected, but got %q", tc.exp, got)
+			}
+			if got := tc.key.UUID().String(); got != tc.exp {
+				t.Errorf("%q expected, but got %q", tc.exp, got)
+			}
+			got, err := snow.ParseUUID(strings.ToUpper(tc.exp))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got != tc.key {
+				t.Errorf("key %v expected, but got %v", tc.key, got)
+			}
+		})
+	}
+
+	var generator snow.Generator
+	key := generator.Create(0)
+	u := key.UUID()
+	if got := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5]); got != key.Time().UnixMilli() {
+		t.Errorf("UUID timestamp %d expected, but got %d", key.Time().UnixMilli(), got)
+	}
+}
+
+func TestLayoutUUID(t *testing.T) {
+	t.Parallel()
+	for _, cfg := range []snow.LayoutConfig{
+		{},
+		{AppBits: 10, Epoch: time.UnixMilli(1288834974657)},
+		{TimestampBits: 39, Tick: 10 * time.Millisecond, AppBits: 16},
+		{TimestampBits: 30, Tick: time.Hour, AppBits: 8},
+		{TimestampBits: 28, Tick: time.Second, AppBits: 32},
+		{TimestampBits: 54, AppBits: 4},
+	} {
+		layout, err := snow.NewLayoutWith(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock := newTestClock()
+		generator := snow.New(0, snow.WithClock(clock), snow.WithLayout(layout))
+		var lastUUID string
+		for i := range 50 {
+			key := generator.Create(layout.MaxAppID())
+			u, errU := layout.UUID(key)
+			if errU != nil {
+				t.Errorf("%v: %v", cfg, errU)
+				continue
+			}
+			ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+			if exp := layout.Time(key).UnixMilli(); ms != exp {
+				t.Errorf("%v: UUID timestamp %d expected, but got %d", cfg, exp, ms)
+			}
+			got, errP := layout.ParseUUID(u.String())
+			if errP != nil || got != key {
+				t.Errorf("%v: key %v expected, but got %v / %v", cfg, key, got, errP)
+			}
+			if s := u.String(); s <= lastUUID {
+				t.Errorf("%v: UUID order not retained: %q -> %q", cfg, lastUUID, s)
+			} else {
+				lastUUID = s
+			}
+			clock.now = clock.now.Add(time.Duration(i%3) * 7 * time.Millisecond)
+		}
+	}
+
+	layout, err := snow.NewLayoutWith(snow.LayoutConfig{TimestampBits: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, errU := layout.UUID(math.MaxUint64); !errors.Is(errU, snow.ErrTimestamp) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrTimestamp, errU, u)
+	}
+	sony, err := snow.NewLayoutWith(snow.LayoutConfig{TimestampBits: 39, Tick: 10 * time.Millisecond, AppBits: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1ms after the default epoch is not a multiple of the tick.
+	if key, errK := sony.KeyFromUUID(snow.Key(1 << 22).UUID()); !errors.Is(errK, snow.ErrUUID) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrUUID, errK, key)
+	}
+}
+
+func TestParseEncodingsError(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		name  string
+		parse func(string) (snow.Key, error)
+		s     string
+		err   error
+	}{
+		{"uuid-empty", snow.ParseUUID, "", snow.ErrEmptyKey},
+		{"uuid-short", snow.ParseUUID, "018fd118-9400-7000-8000-00000000000", snow.ErrLength},
+		{"uuid-long", snow.ParseUUID, "018fd118-9400-7000-8000-0000000000000", snow.ErrLength},
+		{"uuid-hyphen", snow.ParseUUID, "018fd118-9400-7000-8000+000000000000", snow.ErrBadChar},
+		{"uuid-digit", snow.ParseUUID, "018fd118-9400-7000-8000-00000000000g", snow.ErrBadChar},
+		{"uuid-version", snow.ParseUUID, "018fd118-9400-4000-8000-000000000000", snow.ErrUUID},
+		{"uuid-variant", snow.ParseUUID, "018fd118-9400-7000-c000-000000000000", snow.ErrUUID},
+		{"uuid-unused", snow.ParseUUID, "018fd118-9400-7000-8000-000000000001", snow.ErrUUID},
+		{"uuid-early", snow.ParseUUID, "018fd118-93ff-7000-8000-000000000000", snow.ErrUUID},
+		{"uuid-late", snow.ParseUUID, "ffffffff-ffff-7000-8000-000000000000", snow.ErrUUID},
+		{"hex-empty", snow.ParseHex, "", snow.ErrEmptyKey},
+		{"hex-digit", snow.ParseHex, "0123x", snow.ErrBadChar},
+		{"hex-overflow", snow.ParseHex, "10000000000000000", snow.ErrOverflow},
+		{"base58-empty", snow.ParseBase58, "", snow.ErrEmptyKey},
+		{"base58-zero", snow.ParseBase58, "10", snow.ErrBadChar},
+		{"base58-l", snow.ParseBase58, "1l", snow.ErrBadChar},
+		{"base58-overflow", snow.ParseBase58, "jpXCZedGfVR", snow.ErrOverflow},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.parse(tc.s)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("error %v expected, but got %v / %v", tc.err, err, got)
+			}
+		})
+	}
+
+	if _, err := snow.ParseHex("0x"); err == nil || !strings.HasPrefix(err.Error(), "non hex character x") {
+		t.Error("wrong error message:", err)
+	}
+	if _, err := snow.ParseUUID("abc"); err == nil || err.Error() != `UUID has wrong length 3: "abc"` {
+		t.Error("wrong error message:", err)
+	}
+	if got, err := snow.ParseBase58("jpXCZedGfVQ"); err != nil || got != math.MaxUint64 {
+		t.Errorf("max key expected, but got %v / %v", got, err)
+	}
+}
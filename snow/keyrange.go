@@ -0,0 +1,131 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"fmt"
+	"iter"
+	"time"
+)
+
+// KeyRange is a closed interval of keys. Since keys are ordered by time, it
+// can be used for range scans over key-ordered storage, e.g. to retrieve all
+// records created in a given time span.
+//
+// A range with Min > Max is empty.
+type KeyRange struct {
+	Min Key // Lowest key of the range.
+	Max Key // Highest key of the range.
+}
+
+// MinKeyAt returns the lowest possible key of the default layout for the
+// given time. The result does not depend on the number of application bits.
+//
+// If the time is before 2024-06-01 or too far in the future, the lowest key
+// of the nearest possible time is returned, together with an error wrapping
+// [ErrTimestamp].
+func MinKeyAt(t time.Time) (Key, error) { return Layout{}.MinKeyAt(t) }
+
+// MaxKeyAt returns the highest possible key of the default layout for the
+// given time. The result does not depend on the number of application bits.
+//
+// If the time is before 2024-06-01 or too far in the future, the highest key
+// of the nearest possible time is returned, together with an error wrapping
+// [ErrTimestamp].
+func MaxKeyAt(t time.Time) (Key, error) { return Layout{}.MaxKeyAt(t) }
+
+// TimeRange returns the key range of all keys of the default layout that were
+// created between the two given times, both inclusive.
+//
+// Times out of the possible range are clamped, as with [MinKeyAt] and
+// [MaxKeyAt], and an error wrapping [ErrTimestamp] is returned together with
+// the clamped range.
+func TimeRange(from, to time.Time) (KeyRange, error) { return Layout{}.TimeRange(from, to) }
+
+// MinKeyAt returns the lowest possible key of the layout for the given time.
+//
+// See [MinKeyAt] for the handling of out of range values.
+func (l Layout) MinKeyAt(t time.Time) (Key, error) {
+	ts, err := l.clampTime(t)
+	return l.key(ts, 0, 0), err
+}
+
+// MaxKeyAt returns the highest possible key of the layout for the given time.
+//
+// See [MaxKeyAt] for the handling of out of range values.
+func (l Layout) MaxKeyAt(t time.Time) (Key, error) {
+	ts, err := l.clampTime(t)
+	return l.key(ts, 0, 0) | l.randomMask(), err
+}
+
+// TimeRange returns the key range of all keys of the layout that were created
+// between the two given times, both inclusive.
+//
+// See [TimeRange] for the handling of out of range values.
+func (l Layout) TimeRange(from, to time.Time) (KeyRange, error) {
+	minKey, errMin := l.MinKeyAt(from)
+	maxKey, errMax := l.MaxKeyAt(to)
+	if errMin != nil {
+		return KeyRange{Min: minKey, Max: maxKey}, errMin
+	}
+	return KeyRange{Min: minKey, Max: maxKey}, errMax
+}
+
+func (l Layout) clampTime(t time.Time) (int64, error) {
+	ts := l.timestamp(t)
+	if ts < 0 {
+		return 0, fmt.Errorf("%w: %v (min: 0)", ErrTimestamp, ts)
+	}
+	if maxTS := l.maxTimestamp(); ts > maxTS {
+		return maxTS, fmt.Errorf("%w: %v (max: %v)", ErrTimestamp, ts, maxTS)
+	}
+	return ts, nil
+}
+
+// IsEmpty returns true, if the range does not contain any key.
+func (kr KeyRange) IsEmpty() bool { return kr.Min > kr.Max }
+
+// Contains returns true, if the given key is an element of the range.
+func (kr KeyRange) Contains(key Key) bool { return kr.Min <= key && key <= kr.Max }
+
+// Overlaps returns true, if both ranges have at least one key in common.
+func (kr KeyRange) Overlaps(other KeyRange) bool {
+	return !kr.IsEmpty() && !other.IsEmpty() && kr.Min <= other.Max && other.Min <= kr.Max
+}
+
+// Buckets returns an iterator of all sub-ranges of the range, where all keys
+// of a sub-range have the same timestamp of the default layout. They are
+// produced in increasing order.
+func (kr KeyRange) Buckets() iter.Seq[KeyRange] { return Layout{}.Buckets(kr) }
+
+// Buckets returns an iterator of all sub-ranges of the given range, where all
+// keys of a sub-range have the same timestamp of the layout.
+func (l Layout) Buckets(kr KeyRange) iter.Seq[KeyRange] {
+	return func(yield func(KeyRange) bool) {
+		if kr.IsEmpty() {
+			return
+		}
+		shift, randomMask := l.randomBits(), l.randomMask()
+		lastTS := kr.Max >> shift
+		for ts := kr.Min >> shift; ts <= lastTS; ts++ {
+			bucket := KeyRange{
+				Min: max(kr.Min, ts<<shift),
+				Max: min(kr.Max, ts<<shift|randomMask),
+			}
+			if !yield(bucket) {
+				return
+			}
+		}
+	}
+}
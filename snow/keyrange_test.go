@@ -0,0 +1,166 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestMinMaxKeyAt(t *testing.T) {
+	t.Parallel()
+	clock := newTestClock()
+	for appBits := uint(0); appBits <= snow.MaxAppBits; appBits++ {
+		generator := snow.New(appBits, snow.WithClock(clock))
+		minKey, err := snow.MinKeyAt(clock.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		maxKey, err := snow.MaxKeyAt(clock.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, appID := range []uint{0, generator.MaxAppID()} {
+			key := generator.Create(appID)
+			if key < minKey || key > maxKey {
+				t.Errorf("key %v not in range %v..%v", key, minKey, maxKey)
+			}
+		}
+		if !minKey.Time().Equal(clock.now) || !maxKey.Time().Equal(clock.now) {
+			t.Errorf("keys %v / %v should have time %v", minKey, maxKey, clock.now)
+		}
+		clock.now = clock.now.Add(time.Millisecond)
+	}
+
+	epoch := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	var testcases = []struct {
+		name     string
+		t        time.Time
+		min, max snow.Key
+		err      error
+	}{
+		{"epoch", epoch, 0, 0x3fffff, nil},
+		{"before-epoch", epoch.Add(-time.Millisecond), 0, 0x3fffff, snow.ErrTimestamp},
+		{"after-end", time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC), math.MaxUint64 - 0x3fffff, math.MaxUint64, snow.ErrTimestamp},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			minKey, err := snow.MinKeyAt(tc.t)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("error %v expected, but got %v", tc.err, err)
+			}
+			if minKey != tc.min {
+				t.Errorf("min key %v expected, but got %v", tc.min, minKey)
+			}
+			maxKey, err := snow.MaxKeyAt(tc.t)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("error %v expected, but got %v", tc.err, err)
+			}
+			if maxKey != tc.max {
+				t.Errorf("max key %v expected, but got %v", tc.max, maxKey)
+			}
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	t.Parallel()
+	start := newTestClock().now
+	kr, err := snow.TimeRange(start, start.Add(2*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kr.IsEmpty() {
+		t.Error("range must not be empty:", kr)
+	}
+	clock := newTestClock()
+	generator := snow.New(4, snow.WithClock(clock))
+	for i := range 4 {
+		key := generator.Create(15)
+		if got := kr.Contains(key); got != (i < 3) {
+			t.Errorf("range %v contains key %v (%v): %v", kr, key, key.Time(), got)
+		}
+		clock.now = clock.now.Add(time.Millisecond)
+	}
+	if key := generator.Create(0); kr.Contains(key) {
+		t.Errorf("range %v must not contain key %v", kr, key)
+	}
+
+	epoch := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	kr, err = snow.TimeRange(epoch.Add(-time.Hour), epoch)
+	if !errors.Is(err, snow.ErrTimestamp) {
+		t.Errorf("error %v expected, but got %v", snow.ErrTimestamp, err)
+	}
+	if exp := (snow.KeyRange{Min: 0, Max: 0x3fffff}); kr != exp {
+		t.Errorf("range %v expected, but got %v", exp, kr)
+	}
+	if kr, err = snow.TimeRange(start, start.Add(-time.Millisecond)); err != nil || !kr.IsEmpty() {
+		t.Errorf("empty range expected, but got %v / %v", kr, err)
+	}
+}
+
+func TestKeyRangeOverlaps(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		name string
+		a, b snow.KeyRange
+		exp  bool
+	}{
+		{"same", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 1, Max: 5}, true},
+		{"inside", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 2, Max: 3}, true},
+		{"left", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 0, Max: 1}, true},
+		{"right", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 5, Max: 7}, true},
+		{"before", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 0, Max: 0}, false},
+		{"after", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 6, Max: 9}, false},
+		{"empty", snow.KeyRange{Min: 1, Max: 5}, snow.KeyRange{Min: 3, Max: 2}, false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Overlaps(tc.b); got != tc.exp {
+				t.Errorf("%v overlaps %v: %v expected, but got %v", tc.a, tc.b, tc.exp, got)
+			}
+			if got := tc.b.Overlaps(tc.a); got != tc.exp {
+				t.Errorf("%v overlaps %v: %v expected, but got %v", tc.b, tc.a, tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestKeyRangeBuckets(t *testing.T) {
+	t.Parallel()
+	const ms = 1 << 22
+	var testcases = []struct {
+		name string
+		kr   snow.KeyRange
+		exp  []snow.KeyRange
+	}{
+		{"empty", snow.KeyRange{Min: 1, Max: 0}, nil},
+		{"single", snow.KeyRange{Min: 3, Max: 7}, []snow.KeyRange{{Min: 3, Max: 7}}},
+		{"two", snow.KeyRange{Min: 3, Max: ms + 7}, []snow.KeyRange{{Min: 3, Max: ms - 1}, {Min: ms, Max: ms + 7}}},
+		{"three", snow.KeyRange{Min: 0, Max: 3*ms - 1}, []snow.KeyRange{{Min: 0, Max: ms - 1}, {Min: ms, Max: 2*ms - 1}, {Min: 2 * ms, Max: 3*ms - 1}}},
+		{"last", snow.KeyRange{Min: math.MaxUint64 - 1, Max: math.MaxUint64}, []snow.KeyRange{{Min: math.MaxUint64 - 1, Max: math.MaxUint64}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := slices.Collect(tc.kr.Buckets()); !slices.Equal(got, tc.exp) {
+				t.Errorf("%v expected, but got %v", tc.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Layout describes how the bits of a [Key] are split into a timestamp, an
+// application defined value, and a sequence number. It also defines the epoch
+// of the timestamp, and its resolution.
+//
+// The zero value is the default layout without application defined bits: 42
+// bits for timestamps in milliseconds since 2024-06-01, 22 bits for sequence
+// numbers.
+type Layout struct {
+	epochOff int64 // difference of the epoch to epochAdjust, in milliseconds
+	tick     int64 // duration of one timestamp unit in milliseconds; 0 means 1
+	tsBits   uint  // number of bits for the timestamp; 0 means timestampBits
+	appBits  uint  // number of bits for application use
+}
+
+// NewLayout returns the default layout with the given number of bits for
+// application use.
+func NewLayout(appBits uint) Layout {
+	if appBits > MaxAppBits {
+		panic(fmt.Sprintf("key layout need too many bits (max %d): %v", appBits, MaxAppBits))
+	}
+	return Layout{appBits: appBits}
+}
+
+// LayoutConfig contains all values to configure a [Layout]. A zero field
+// value denotes the default value.
+type LayoutConfig struct {
+	// Epoch is the time of the timestamp value zero. Default: 2024-06-01 UTC.
+	Epoch time.Time
+
+	// TimestampBits is the number of bits used for the timestamp. Default: 42.
+	TimestampBits uint
+
+	// Tick is the duration of one timestamp unit. It must be a multiple of
+	// one millisecond. Default: 1ms.
+	Tick time.Duration
+
+	// AppBits is the number of bits for application use. Default: 0.
+	AppBits uint
+}
+
+// MinSeqBits is the minimum number of bits for the sequence number.
+const MinSeqBits = 2
+
+// ErrLayout signals an invalid layout configuration.
+var ErrLayout = errors.New("invalid layout")
+
+// NewLayoutWith returns a layout as configured. All keys of the default
+// layout, with TimestampBits=42 and Tick=1ms, have the same bits as without
+// a configuration.
+//
+// For example, a Twitter Snowflake compatible layout uses an epoch of
+// 2010-11-04 01:42:54.657 UTC and 10 application bits. Since Twitter does not
+// use the sign bit, the 42 timestamp bits of the default will do. A Sonyflake
+// like layout uses 39 timestamp bits, a tick of 10ms, and 16 application bits.
+//
+// The timestamp must have at least one bit, and at least [MinSeqBits] must
+// remain for the sequence number. The latest timestamp must be representable
+// in milliseconds since the Unix epoch, as an int64.
+func NewLayoutWith(cfg LayoutConfig) (Layout, error) {
+	var l Layout
+	if !cfg.Epoch.IsZero() {
+		epoch := cfg.Epoch.UnixMilli()
+		if epoch < math.MinInt64+epochAdjust {
+			return Layout{}, fmt.Errorf("%w: epoch %v out of range", ErrLayout, cfg.Epoch)
+		}
+		l.epochOff = epoch - epochAdjust
+	}
+	if tick := cfg.Tick; tick != 0 {
+		if tick < time.Millisecond || tick%time.Millisecond != 0 {
+			return Layout{}, fmt.Errorf("%w: tick %v is not a multiple of 1ms", ErrLayout, tick)
+		}
+		if tick != time.Millisecond {
+			l.tick = tick.Milliseconds()
+		}
+	}
+	if tsBits := cfg.TimestampBits; tsBits != 0 && tsBits != timestampBits {
+		l.tsBits = tsBits
+	}
+	if tsBits := l.TimestampBits(); tsBits+cfg.AppBits+MinSeqBits > 64 {
+		return Layout{}, fmt.Errorf("%w: %d timestamp bits and %d application bits leave less than %d sequence bits",
+			ErrLayout, tsBits, cfg.AppBits, MinSeqBits)
+	}
+	epoch := epochAdjust + l.epochOff
+	if maxTS, tick := l.maxTimestamp(), l.tickMilli(); maxTS > (math.MaxInt64-max(epoch, 0))/tick {
+		return Layout{}, fmt.Errorf("%w: %d timestamp bits with tick %v exceed the time range after epoch %v",
+			ErrLayout, l.TimestampBits(), l.Tick(), l.Epoch())
+	}
+	l.appBits = cfg.AppBits
+	return l, nil
+}
+
+// Config returns the configuration of the layout.
+func (l Layout) Config() LayoutConfig {
+	return LayoutConfig{
+		Epoch:         l.Epoch(),
+		TimestampBits: l.TimestampBits(),
+		Tick:          l.Tick(),
+		AppBits:       l.appBits,
+	}
+}
+
+// Parts contains all components of a [Key].
+type Parts struct {
+	Time  time.Time // Timestamp, with the resolution of the layout tick.
+	AppID uint      // Application defined value.
+	Seq   uint      // Sequence number.
+}
+
+// ErrSeq signals a sequence number that is too large.
+var ErrSeq = errors.New("sequence number out of range")
+
+// Epoch returns the time of the timestamp value zero.
+func (l Layout) Epoch() time.Time { return time.UnixMilli(epochAdjust + l.epochOff) }
+
+// Tick returns the duration of one timestamp unit.
+func (l Layout) Tick() time.Duration { return time.Duration(l.tickMilli()) * time.Millisecond }
+
+// TimestampBits returns the number of bits used for the timestamp.
+func (l Layout) TimestampBits() uint {
+	if l.tsBits == 0 {
+		return timestampBits
+	}
+	return l.tsBits
+}
+
+// AppBits returns the number of bits used for the application defined value.
+func (l Layout) AppBits() uint { return l.appBits }
+
+// SeqBits returns the number of bits used for the sequence number.
+func (l Layout) SeqBits() uint { return l.randomBits() - l.appBits }
+
+// MaxAppID returns the maximum application defined value.
+func (l Layout) MaxAppID() uint { return 1<<l.appBits - 1 }
+
+// MaxSeq returns the maximum sequence number.
+func (l Layout) MaxSeq() uint { return 1<<l.SeqBits() - 1 }
+
+// MaxTime returns the latest time that can be stored in a key.
+func (l Layout) MaxTime() time.Time { return l.timeOf(l.maxTimestamp()) }
+
+// Time returns the timestamp value of the given key.
+func (l Layout) Time(key Key) time.Time { return l.timeOf(int64(uint64(key) >> l.randomBits())) }
+
+// AppID returns the application defined value of the given key.
+func (l Layout) AppID(key Key) uint {
+	return uint((key & l.randomMask()) >> l.SeqBits())
+}
+
+// Seq returns the sequence number of the given key.
+func (l Layout) Seq(key Key) uint {
+	return uint(key) & l.MaxSeq()
+}
+
+// Decompose splits the given key into its parts.
+func (l Layout) Decompose(key Key) Parts {
+	return Parts{Time: l.Time(key), AppID: l.AppID(key), Seq: l.Seq(key)}
+}
+
+// Compose builds a key from the given parts. The time is truncated to the
+// resolution of the layout.
+//
+// An error is returned, if the time cannot be stored in a key ([ErrTimestamp]),
+// if the application value is too large ([ErrAppID]), or if the sequence
+// number is too large ([ErrSeq]).
+func (l Layout) Compose(p Parts) (Key, error) {
+	ts := l.timestamp(p.Time)
+	if maxTS := l.maxTimestamp(); ts < 0 || ts > maxTS {
+		return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrTimestamp, ts, maxTS)
+	}
+	if maxID := l.MaxAppID(); p.AppID > maxID {
+		return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrAppID, p.AppID, maxID)
+	}
+	if maxSeq := l.MaxSeq(); p.Seq > maxSeq {
+		return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrSeq, p.Seq, maxSeq)
+	}
+	return l.key(ts, p.AppID, uint64(p.Seq)), nil
+}
+
+// key builds a key from its components, without checking their range.
+func (l Layout) key(ts int64, appID uint, seq uint64) Key {
+	// tsBits=ts, appBits=appID, 64-tsBits-appBits=seq
+	return Key((uint64(ts) << l.randomBits()) | (uint64(appID) << l.SeqBits()) | seq)
+}
+
+func (l Layout) tickMilli() int64 { return max(l.tick, 1) }
+
+// randomBits returns the number of bits for application value and sequence.
+func (l Layout) randomBits() uint { return 64 - l.TimestampBits() }
+
+func (l Layout) randomMask() Key { return 1<<l.randomBits() - 1 }
+
+func (l Layout) maxTimestamp() int64 { return int64(1<<l.TimestampBits() - 1) }
+
+// timestamp returns the number of ticks since the epoch, rounded down. If
+// the difference overflows, the result is clamped to the range of int64.
+func (l Layout) timestamp(t time.Time) int64 {
+	ms, epoch := t.UnixMilli(), epochAdjust+l.epochOff
+	if epoch < 0 && ms > math.MaxInt64+epoch {
+		return math.MaxInt64
+	}
+	if epoch > 0 && ms < math.MinInt64+epoch {
+		return math.MinInt64
+	}
+	diff := ms - epoch
+	tick := l.tickMilli()
+	if diff < 0 {
+		return (diff - tick + 1) / tick
+	}
+	return diff / tick
+}
+
+func (l Layout) timeOf(ts int64) time.Time {
+	return time.UnixMilli(ts*l.tickMilli() + epochAdjust + l.epochOff)
+}
@@ -0,0 +1,324 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestLayoutBits(t *testing.T) {
+	t.Parallel()
+	for appBits := uint(0); appBits <= snow.MaxAppBits; appBits++ {
+		layout := snow.NewLayout(appBits)
+		if got := layout.TimestampBits() + layout.AppBits() + layout.SeqBits(); got != 64 {
+			t.Errorf("layout %d should have 64 bits, but has %d", appBits, got)
+		}
+		if got := layout.MaxAppID(); got != 1<<appBits-1 {
+			t.Errorf("layout %d: max app id should be %d, but got %d", appBits, 1<<appBits-1, got)
+		}
+		if got := layout.MaxSeq(); got != 1<<(22-appBits)-1 {
+			t.Errorf("layout %d: max seq should be %d, but got %d", appBits, 1<<(22-appBits)-1, got)
+		}
+		if got := snow.New(appBits).Layout(); got != layout {
+			t.Errorf("generator layout should be %v, but got %v", layout, got)
+		}
+	}
+
+	var generator snow.Generator
+	if got := generator.MaxAppID(); got != 0 {
+		t.Error("zero generator should have max app id 0, but got", got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("should panic, but did not")
+		}
+	}()
+	_ = snow.NewLayout(snow.MaxAppBits + 1)
+}
+
+func TestLayoutDecompose(t *testing.T) {
+	t.Parallel()
+	for appBits := uint(0); appBits <= snow.MaxAppBits; appBits++ {
+		generator := snow.New(appBits)
+		layout := generator.Layout()
+		for range 64 {
+			appID := uint(rand.Int63n(int64(layout.MaxAppID()) + 1))
+			key := generator.Create(appID)
+			parts := layout.Decompose(key)
+			if !parts.Time.Equal(key.Time()) || parts.AppID != appID || parts.Seq != generator.KeySeq(key) {
+				t.Errorf("key %v decomposed into %v", key, parts)
+				continue
+			}
+			got, err := layout.Compose(parts)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if got != key {
+				t.Errorf("parts %v should compose to %v, but got %v", parts, key, got)
+			}
+		}
+	}
+}
+
+func TestLayoutCompose(t *testing.T) {
+	t.Parallel()
+	layout := snow.NewLayout(4)
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	key, err := layout.Compose(snow.Parts{Time: start, AppID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Time().Equal(start) || layout.AppID(key) != 3 || layout.Seq(key) != 0 {
+		t.Errorf("wrong key %v: %v", key, layout.Decompose(key))
+	}
+	prev, err := layout.Compose(snow.Parts{Time: start.Add(-time.Millisecond), AppID: 3, Seq: layout.MaxSeq()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev >= key {
+		t.Errorf("key %v should be less than %v", prev, key)
+	}
+	trunc, err := layout.Compose(snow.Parts{Time: start.Add(999 * time.Microsecond), AppID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trunc != key {
+		t.Errorf("time should be truncated to %v, but got %v", key, trunc)
+	}
+
+	var testcases = []struct {
+		name  string
+		parts snow.Parts
+		err   error
+	}{
+		{"before-epoch", snow.Parts{Time: time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC)}, snow.ErrTimestamp},
+		{"after-end", snow.Parts{Time: time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)}, snow.ErrTimestamp},
+		{"app-id", snow.Parts{Time: start, AppID: 16}, snow.ErrAppID},
+		{"seq", snow.Parts{Time: start, Seq: 1 << 18}, snow.ErrSeq},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, err := layout.Compose(tc.parts); !errors.Is(err, tc.err) {
+				t.Errorf("error %v expected, but got %v / %v", tc.err, err, got)
+			}
+		})
+	}
+}
+
+func TestNewLayoutWith(t *testing.T) {
+	t.Parallel()
+	for appBits := uint(0); appBits <= snow.MaxAppBits; appBits++ {
+		exp := snow.NewLayout(appBits)
+		for _, cfg := range []snow.LayoutConfig{
+			{AppBits: appBits},
+			{Epoch: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), TimestampBits: 42, Tick: time.Millisecond, AppBits: appBits},
+			exp.Config(),
+		} {
+			layout, err := snow.NewLayoutWith(cfg)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if layout != exp {
+				t.Errorf("config %v should result in layout %v, but got %v", cfg, exp, layout)
+			}
+		}
+	}
+
+	var testcases = []struct {
+		name string
+		cfg  snow.LayoutConfig
+	}{
+		{"tick-too-small", snow.LayoutConfig{Tick: time.Microsecond}},
+		{"tick-fraction", snow.LayoutConfig{Tick: 1500 * time.Microsecond}},
+		{"tick-negative", snow.LayoutConfig{Tick: -time.Millisecond}},
+		{"too-many-ts-bits", snow.LayoutConfig{TimestampBits: 63}},
+		{"too-many-app-bits", snow.LayoutConfig{TimestampBits: 40, AppBits: 23}},
+		{"time-overflow", snow.LayoutConfig{TimestampBits: 62, Tick: 10 * time.Millisecond}},
+		{"time-overflow-tick", snow.LayoutConfig{TimestampBits: 62, Tick: 2 * time.Millisecond}},
+		{"time-overflow-epoch", snow.LayoutConfig{TimestampBits: 62, Epoch: time.UnixMilli(math.MaxInt64/2 + 2)}},
+		{"epoch-out-of-range", snow.LayoutConfig{Epoch: time.UnixMilli(math.MinInt64)}},
+	}
+	for _, cfg := range []snow.LayoutConfig{
+		{TimestampBits: 62},
+		{TimestampBits: 61, Tick: 2 * time.Millisecond},
+		{TimestampBits: 30, Tick: time.Hour, Epoch: time.UnixMilli(-1 << 50)},
+	} {
+		layout, err := snow.NewLayoutWith(cfg)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if maxTime := layout.MaxTime(); !maxTime.After(layout.Epoch()) {
+			t.Errorf("config %v: max time %v is not after epoch %v", cfg, maxTime, layout.Epoch())
+		}
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, err := snow.NewLayoutWith(tc.cfg); !errors.Is(err, snow.ErrLayout) {
+				t.Errorf("error %v expected, but got %v / %v", snow.ErrLayout, err, got)
+			}
+		})
+	}
+}
+
+func TestLayoutDefaultCompatible(t *testing.T) {
+	t.Parallel()
+	layout, err := snow.NewLayoutWith(snow.LayoutConfig{
+		Epoch:         time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+		TimestampBits: 42,
+		Tick:          time.Millisecond,
+		AppBits:       5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock1, clock2 := newTestClock(), newTestClock()
+	gen1 := snow.New(5, snow.WithClock(clock1))
+	gen2 := snow.New(0, snow.WithClock(clock2), snow.WithLayout(layout))
+	for i := range 100 {
+		key1, key2 := gen1.Create(uint(i%32)), gen2.Create(uint(i%32))
+		if key1 != key2 {
+			t.Errorf("keys differ: %v != %v", key1, key2)
+		}
+		if got := layout.Time(key2); !got.Equal(key1.Time()) {
+			t.Errorf("time %v expected, but got %v", key1.Time(), got)
+		}
+		clock1.now = clock1.now.Add(time.Duration(i) * time.Microsecond * 100)
+		clock2.now = clock1.now
+	}
+}
+
+func TestLayoutTwitter(t *testing.T) {
+	t.Parallel()
+	layout, err := snow.NewLayoutWith(snow.LayoutConfig{
+		Epoch:   time.UnixMilli(1288834974657),
+		AppBits: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := layout.SeqBits(); got != 12 {
+		t.Errorf("12 sequence bits expected, but got %d", got)
+	}
+	const id = snow.Key(1212092628029698048)
+	parts := layout.Decompose(id)
+	if exp := time.Date(2019, time.December, 31, 19, 26, 16, 771*int(time.Millisecond), time.UTC); !parts.Time.Equal(exp) {
+		t.Errorf("time %v expected, but got %v", exp, parts.Time)
+	}
+	if key, errCompose := layout.Compose(parts); errCompose != nil || key != id {
+		t.Errorf("key %v expected, but got %v / %v", id, key, errCompose)
+	}
+}
+
+func TestLayoutTick(t *testing.T) {
+	t.Parallel()
+	const tick = 10 * time.Millisecond
+	layout, err := snow.NewLayoutWith(snow.LayoutConfig{
+		Epoch:         time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		TimestampBits: 39,
+		Tick:          tick,
+		AppBits:       16,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := layout.Tick(); got != tick {
+		t.Errorf("tick %v expected, but got %v", tick, got)
+	}
+	if got := layout.MaxTime(); got.Year() != 2199 {
+		t.Errorf("max time should be in year 2199, but got %v", got)
+	}
+
+	clock := newTestClock()
+	clock.now = clock.now.Add(7 * time.Millisecond)
+	generator := snow.New(0, snow.WithClock(clock), snow.WithLayout(layout))
+	if got := generator.MaxAppID(); got != 1<<16-1 {
+		t.Errorf("max app id %d expected, but got %d", 1<<16-1, got)
+	}
+	var lastKey snow.Key
+	for i := range layout.MaxSeq() + 2 {
+		key := generator.Create(4711)
+		if key <= lastKey {
+			t.Errorf("key does not increase: %v -> %v", lastKey, key)
+		}
+		lastKey = key
+		parts := layout.Decompose(key)
+		if parts.AppID != 4711 {
+			t.Errorf("app id 4711 expected, but got %d", parts.AppID)
+		}
+		if exp := clock.now.Truncate(tick); !parts.Time.Equal(exp) {
+			t.Errorf("%d: time %v expected, but got %v", i, exp, parts.Time)
+		}
+	}
+	if clock.slept != tick {
+		t.Errorf("should sleep %v, but slept %v", tick, clock.slept)
+	}
+
+	minKey, err := layout.MinKeyAt(clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxKey, err := layout.MaxKeyAt(clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kr := (snow.KeyRange{Min: minKey, Max: maxKey}); !kr.Contains(lastKey) {
+		t.Errorf("range %v does not contain %v", kr, lastKey)
+	}
+	if _, err = layout.MinKeyAt(layout.Epoch().Add(-time.Millisecond)); !errors.Is(err, snow.ErrTimestamp) {
+		t.Errorf("error %v expected, but got %v", snow.ErrTimestamp, err)
+	}
+}
+
+func TestLayoutTimestampOverflow(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name  string
+		epoch time.Time
+		t     time.Time
+		atMax bool
+	}{
+		{"future", time.UnixMilli(-(1 << 62)), time.UnixMilli(math.MaxInt64), true},
+		{"past", time.UnixMilli(1 << 62), time.UnixMilli(math.MinInt64), false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			layout, err := snow.NewLayoutWith(snow.LayoutConfig{Epoch: tc.epoch, TimestampBits: 20})
+			if err != nil {
+				t.Fatal(err)
+			}
+			exp, _ := layout.MinKeyAt(layout.Epoch())
+			if tc.atMax {
+				exp, _ = layout.MinKeyAt(layout.MaxTime())
+			}
+			got, err := layout.MinKeyAt(tc.t)
+			if !errors.Is(err, snow.ErrTimestamp) {
+				t.Errorf("error %v expected, but got %v", snow.ErrTimestamp, err)
+			}
+			if got != exp {
+				t.Errorf("key %v expected, but got %v", exp, got)
+			}
+		})
+	}
+}
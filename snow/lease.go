@@ -0,0 +1,425 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"t73f.de/r/zero/oso"
+)
+
+// LeaseBackend manages leases of application values. A lease grants the
+// exclusive use of an application value for a limited time, so that multiple
+// replicas of an application can create keys without collisions.
+type LeaseBackend interface {
+	// Claim acquires a lease for an unused application value in the range
+	// 0..maxID, and returns the value and the expiry time of the lease. If
+	// all values are in use, an error wrapping [ErrNoAppID] is returned.
+	Claim(ctx context.Context, maxID uint, ttl time.Duration) (uint, time.Time, error)
+
+	// Renew extends the lease of the given application value and returns
+	// the new expiry time. If the lease was taken over by another owner, an
+	// error wrapping [ErrLeaseLost] is returned.
+	Renew(ctx context.Context, appID uint, ttl time.Duration) (time.Time, error)
+
+	// Release gives up the lease of the given application value.
+	Release(ctx context.Context, appID uint) error
+}
+
+// Errors returned by lease functions.
+var (
+	// ErrNoAppID signals that all application values are leased.
+	ErrNoAppID = errors.New("no application value available")
+
+	// ErrLeaseLost signals that a lease was taken over by another owner.
+	ErrLeaseLost = errors.New("lease lost")
+
+	// ErrLeaseExpired signals that a [Generator] cannot create keys, because
+	// its lease has expired.
+	ErrLeaseExpired = errors.New("lease expired")
+)
+
+// Lease is a lease of an application value, acquired by [AcquireLease].
+type Lease struct {
+	backend LeaseBackend
+	appID   uint
+	ttl     time.Duration
+	expires atomic.Int64 // Unix time in nanoseconds
+}
+
+// AcquireLease claims an application value in the range 0..maxID for the
+// given time to live. Typically, maxID is the result of [Generator.MaxAppID].
+func AcquireLease(ctx context.Context, backend LeaseBackend, maxID uint, ttl time.Duration) (*Lease, error) {
+	appID, expires, err := backend.Claim(ctx, maxID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	lease := &Lease{backend: backend, appID: appID, ttl: ttl}
+	lease.expires.Store(expires.UnixNano())
+	return lease, nil
+}
+
+// AppID returns the leased application value.
+func (l *Lease) AppID() uint { return l.appID }
+
+// Expires returns the expiry time of the lease.
+func (l *Lease) Expires() time.Time { return time.Unix(0, l.expires.Load()) }
+
+// ValidAt returns true, if the lease is valid at the given time.
+func (l *Lease) ValidAt(t time.Time) bool { return t.UnixNano() < l.expires.Load() }
+
+// Renew extends the lease by its time to live.
+func (l *Lease) Renew(ctx context.Context) error {
+	expires, err := l.backend.Renew(ctx, l.appID, l.ttl)
+	if err != nil {
+		if errors.Is(err, ErrLeaseLost) {
+			l.expires.Store(0)
+		}
+		return err
+	}
+	l.expires.Store(expires.UnixNano())
+	return nil
+}
+
+// KeepAlive renews the lease periodically, three times per time to live,
+// until the context is done. If the lease is lost, an error is returned.
+// Other errors are ignored, as long as the lease is valid.
+//
+// It is typically called in its own goroutine.
+func (l *Lease) KeepAlive(ctx context.Context) error {
+	ticker := time.NewTicker(max(l.ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := l.Renew(ctx); err != nil {
+				if errors.Is(err, ErrLeaseLost) || !l.ValidAt(time.Now()) {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Release gives up the lease. Afterwards, the lease is not valid anymore.
+func (l *Lease) Release(ctx context.Context) error {
+	l.expires.Store(0)
+	return l.backend.Release(ctx, l.appID)
+}
+
+// WithLease sets the lease of a [Generator]. The generator refuses to create
+// keys, if the lease is not valid at the current time of the generator clock.
+// [Generator.CreateE] will return an error wrapping [ErrLeaseExpired].
+//
+// The generator does not check the application value given to
+// [Generator.Create], because an application may combine the leased value with
+// other application defined data.
+func WithLease(lease *Lease) GeneratorOption {
+	return func(gen *Generator) { gen.lease = lease }
+}
+
+// FileLeaseBackend is a [LeaseBackend] that stores leases as files in a
+// directory of the local file system. Every application value is represented
+// by a file, which contains the owner and the expiry time of the lease.
+//
+// It is intended for processes on the same host, or on hosts sharing a
+// reliable file system that supports hard links and atomic renames.
+//
+// A new lease file is created atomically with its content, so that only one
+// process can claim an unused application value. Taking over an expired
+// lease, renewing, and releasing a lease are serialized by an additional lock
+// file per application value, which is created exclusively. If a process dies
+// while holding a lock file, the lock file is removed by other processes after
+// some seconds.
+type FileLeaseBackend struct {
+	dir   string
+	owner string
+}
+
+// NewFileLeaseBackend creates a new [FileLeaseBackend] that uses the given
+// directory. The directory is created, if needed. Every backend has its own
+// unique owner identification.
+func NewFileLeaseBackend(dir string) (*FileLeaseBackend, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return &FileLeaseBackend{dir: dir, owner: hex.EncodeToString(buf[:])}, nil
+}
+
+// Owner returns the owner identification of the backend.
+func (fb *FileLeaseBackend) Owner() string { return fb.owner }
+
+// Claim acquires the lease of the lowest application value that is not used,
+// or whose lease has expired.
+func (fb *FileLeaseBackend) Claim(ctx context.Context, maxID uint, ttl time.Duration) (uint, time.Time, error) {
+	for appID := uint(0); appID <= maxID; appID++ {
+		if err := ctx.Err(); err != nil {
+			return 0, time.Time{}, err
+		}
+		expires := time.Now().Add(ttl)
+		claimed, err := fb.tryClaim(appID, expires)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		if claimed {
+			return appID, expires, nil
+		}
+		if appID == maxID {
+			break
+		}
+	}
+	return 0, time.Time{}, fmt.Errorf("%w (max: %d)", ErrNoAppID, maxID)
+}
+
+func (fb *FileLeaseBackend) tryClaim(appID uint, expires time.Time) (bool, error) {
+	created, err := fb.create(appID, expires)
+	if created || err != nil {
+		return created, err
+	}
+	if _, oldExpires, errRead := fb.read(appID); errRead == nil && time.Now().Before(oldExpires) {
+		return false, nil
+	}
+
+	// Lease has expired, or lease file is corrupt: take over, while holding
+	// the lock.
+	unlock, err := fb.lock(appID)
+	if err != nil {
+		if errors.Is(err, errLeaseLocked) {
+			return false, nil // Another process is taking over
+		}
+		return false, err
+	}
+	defer unlock()
+	_, oldExpires, err := fb.read(appID)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fb.create(appID, expires) // Released in the meantime
+	case err == nil && time.Now().Before(oldExpires):
+		return false, nil // Taken over or renewed in the meantime
+	}
+	if err = fb.write(appID, expires); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// create creates a new lease file atomically, by linking a temporary file
+// with the complete content. If the lease file already exists, false is
+// returned.
+func (fb *FileLeaseBackend) create(appID uint, expires time.Time) (bool, error) {
+	f, err := os.CreateTemp(fb.dir, "new-*.tmp")
+	if err != nil {
+		return false, err
+	}
+	tmpPath := f.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+	_, errWrite := f.WriteString(fb.content(expires))
+	if err = errors.Join(errWrite, f.Close()); err != nil {
+		return false, err
+	}
+	if err = os.Link(tmpPath, fb.path(appID)); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Renew extends the lease of the given application value.
+func (fb *FileLeaseBackend) Renew(ctx context.Context, appID uint, ttl time.Duration) (time.Time, error) {
+	unlock, err := fb.lockWait(ctx, appID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer unlock()
+	if err = fb.checkOwner(appID); err != nil {
+		return time.Time{}, err
+	}
+	expires := time.Now().Add(ttl)
+	if err = fb.write(appID, expires); err != nil {
+		return time.Time{}, err
+	}
+	return expires, nil
+}
+
+// Release removes the lease file of the given application value.
+func (fb *FileLeaseBackend) Release(ctx context.Context, appID uint) error {
+	unlock, err := fb.lockWait(ctx, appID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err = fb.checkOwner(appID); err != nil {
+		return err
+	}
+	return os.Remove(fb.path(appID))
+}
+
+// errLeaseLocked signals that the lock file of a lease is held by another
+// process.
+var errLeaseLocked = errors.New("lease is locked")
+
+// Timing of lease lock files.
+const (
+	leaseLockStale = 10 * time.Second       // Age of the lock file of a dead process
+	leaseLockRetry = 5 * time.Millisecond   // Delay before trying to lock again
+	leaseLockWait  = 500 * time.Millisecond // Maximum time to wait for a lock
+)
+
+// lockWait acquires the lock of the given application value, waiting some
+// time if it is held by another process.
+func (fb *FileLeaseBackend) lockWait(ctx context.Context, appID uint) (func(), error) {
+	deadline := time.Now().Add(leaseLockWait)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		unlock, err := fb.lock(appID)
+		if !errors.Is(err, errLeaseLocked) || time.Now().After(deadline) {
+			return unlock, err
+		}
+		time.Sleep(leaseLockRetry)
+	}
+}
+
+// lock acquires the lock of the given application value, by creating its
+// lock file exclusively. It returns a function to release the lock, or an
+// error wrapping errLeaseLocked, if another process holds the lock.
+func (fb *FileLeaseBackend) lock(appID uint) (func(), error) {
+	path := fb.path(appID) + ".lock"
+	for range 3 {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if broken, errBreak := breakStaleLock(path); errBreak != nil || !broken {
+			if errBreak != nil {
+				return nil, errBreak
+			}
+			break
+		}
+	}
+	return nil, fmt.Errorf("%w: application value %d", errLeaseLocked, appID)
+}
+
+// breakStaleLock removes the given lock file, if it is stale, i.e. its process
+// has presumably died while holding it. It returns true, if the lock may be
+// acquired now.
+//
+// Breaking is serialized by another lock file, so that no process removes a
+// fresh lock file that was created after another process broke the stale one.
+func breakStaleLock(path string) (bool, error) {
+	stale, err := isStaleLock(path)
+	if !stale || err != nil {
+		return stale, err
+	}
+	breakPath := path + ".break"
+	f, err := os.OpenFile(breakPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return false, nil // Another process is breaking the lock
+		}
+		return false, err
+	}
+	_ = f.Close()
+	defer func() { _ = os.Remove(breakPath) }()
+
+	if stale, err = isStaleLock(path); !stale || err != nil {
+		return stale, err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	return true, nil
+}
+
+// isStaleLock returns true, if the lock file is stale or does not exist.
+func isStaleLock(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+		return false, err
+	}
+	return time.Since(info.ModTime()) > leaseLockStale, nil
+}
+
+func (fb *FileLeaseBackend) path(appID uint) string {
+	return filepath.Join(fb.dir, fmt.Sprintf("app-%d.lease", appID))
+}
+
+func (fb *FileLeaseBackend) content(expires time.Time) string {
+	return fb.owner + "\n" + expires.UTC().Format(time.RFC3339Nano) + "\n"
+}
+
+func (fb *FileLeaseBackend) read(appID uint) (string, time.Time, error) {
+	data, err := os.ReadFile(fb.path(appID))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	owner, ts, found := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	if !found {
+		return "", time.Time{}, fmt.Errorf("invalid lease file for application value %d", appID)
+	}
+	expires, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return owner, expires, nil
+}
+
+func (fb *FileLeaseBackend) write(appID uint, expires time.Time) error {
+	f, err := oso.SafeWrite(fb.path(appID))
+	if err != nil {
+		return err
+	}
+	defer f.RollbackIfNeeded()
+
+	_, _ = f.WriteString(fb.content(expires))
+	return f.Close()
+}
+
+func (fb *FileLeaseBackend) checkOwner(appID uint) error {
+	owner, _, err := fb.read(appID)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: application value %d", ErrLeaseLost, appID)
+		}
+		return err
+	}
+	if owner != fb.owner {
+		return fmt.Errorf("%w: application value %d", ErrLeaseLost, appID)
+	}
+	return nil
+}
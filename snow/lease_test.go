@@ -0,0 +1,224 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestFileLease(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend1, err := snow.NewFileLeaseBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend2, err := snow.NewFileLeaseBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend1.Owner() == backend2.Owner() {
+		t.Error("backends must have different owners")
+	}
+
+	lease1, err := snow.AcquireLease(ctx, backend1, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lease2, err := snow.AcquireLease(ctx, backend2, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease1.AppID() != 0 || lease2.AppID() != 1 {
+		t.Errorf("application values 0 and 1 expected, but got %d and %d", lease1.AppID(), lease2.AppID())
+	}
+	if _, err = snow.AcquireLease(ctx, backend1, 1, time.Hour); !errors.Is(err, snow.ErrNoAppID) {
+		t.Errorf("ErrNoAppID expected, but got %v", err)
+	}
+
+	oldExpires := lease1.Expires()
+	if err = lease1.Renew(ctx); err != nil {
+		t.Error(err)
+	} else if lease1.Expires().Before(oldExpires) {
+		t.Errorf("renewed lease expires at %v, before %v", lease1.Expires(), oldExpires)
+	}
+
+	if err = lease2.Release(ctx); err != nil {
+		t.Error(err)
+	}
+	if lease2.ValidAt(time.Now()) {
+		t.Error("released lease must not be valid")
+	}
+	lease3, err := snow.AcquireLease(ctx, backend1, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease3.AppID() != 1 {
+		t.Errorf("released application value 1 expected, but got %d", lease3.AppID())
+	}
+}
+
+func TestFileLeaseTakeover(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend1, err := snow.NewFileLeaseBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend2, err := snow.NewFileLeaseBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lease1, err := snow.AcquireLease(ctx, backend1, 0, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	lease2, err := snow.AcquireLease(ctx, backend2, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease2.AppID() != lease1.AppID() {
+		t.Errorf("expired application value %d expected, but got %d", lease1.AppID(), lease2.AppID())
+	}
+	if err = lease1.Renew(ctx); !errors.Is(err, snow.ErrLeaseLost) {
+		t.Errorf("ErrLeaseLost expected, but got %v", err)
+	}
+	if lease1.ValidAt(time.Now()) {
+		t.Error("lost lease must not be valid")
+	}
+	if err = lease1.Release(ctx); !errors.Is(err, snow.ErrLeaseLost) {
+		t.Errorf("ErrLeaseLost expected on release, but got %v", err)
+	}
+	if !lease2.ValidAt(time.Now()) {
+		t.Error("lease of new owner must still be valid")
+	}
+}
+
+func TestFileLeaseConcurrentTakeover(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const claimers = 16
+	for round := range 10 {
+		dir := t.TempDir()
+		backend, err := snow.NewFileLeaseBackend(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = snow.AcquireLease(ctx, backend, 0, time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Millisecond)
+
+		var wg sync.WaitGroup
+		var won atomic.Int32
+		start := make(chan struct{})
+		for range claimers {
+			b, errB := snow.NewFileLeaseBackend(dir)
+			if errB != nil {
+				t.Fatal(errB)
+			}
+			wg.Go(func() {
+				<-start
+				_, errL := snow.AcquireLease(ctx, b, 0, time.Hour)
+				switch {
+				case errL == nil:
+					won.Add(1)
+				case !errors.Is(errL, snow.ErrNoAppID):
+					t.Error(errL)
+				}
+			})
+		}
+		close(start)
+		wg.Wait()
+		if got := won.Load(); got != 1 {
+			t.Errorf("round %d: exactly one claimer should take over the lease, but %d did", round, got)
+		}
+	}
+}
+
+func TestFileLeaseConcurrentRenew(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend1, err := snow.NewFileLeaseBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend2, err := snow.NewFileLeaseBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lease1, err := snow.AcquireLease(ctx, backend1, 0, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(110 * time.Millisecond)
+
+	// The expired lease is renewed and taken over at the same time: either
+	// the renewal wins and the takeover fails, or the renewal is lost.
+	var wg sync.WaitGroup
+	var errRenew, errClaim error
+	wg.Go(func() { errRenew = lease1.Renew(ctx) })
+	wg.Go(func() { _, errClaim = snow.AcquireLease(ctx, backend2, 0, time.Hour) })
+	wg.Wait()
+	switch {
+	case errRenew == nil && errors.Is(errClaim, snow.ErrNoAppID):
+	case errors.Is(errRenew, snow.ErrLeaseLost) && errClaim == nil:
+	default:
+		t.Errorf("inconsistent result: renew %v, claim %v", errRenew, errClaim)
+	}
+}
+
+func TestGeneratorLease(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	backend, err := snow.NewFileLeaseBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const appBits = 4
+	lease, err := snow.AcquireLease(ctx, backend, snow.NewLayout(appBits).MaxAppID(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := newTestClock()
+	clock.now = time.Now()
+	generator := snow.New(appBits, snow.WithClock(clock), snow.WithLease(lease))
+	key, err := generator.CreateE(lease.AppID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := generator.AppID(key); got != lease.AppID() {
+		t.Errorf("application value %d expected, but got %d", lease.AppID(), got)
+	}
+
+	clock.now = lease.Expires()
+	if _, err = generator.CreateE(lease.AppID()); !errors.Is(err, snow.ErrLeaseExpired) {
+		t.Errorf("ErrLeaseExpired expected, but got %v", err)
+	}
+	if _, err = generator.Reserve(lease.AppID(), 10); !errors.Is(err, snow.ErrLeaseExpired) {
+		t.Errorf("ErrLeaseExpired expected for reservation, but got %v", err)
+	}
+}
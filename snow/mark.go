@@ -0,0 +1,137 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"t73f.de/r/zero/oso"
+)
+
+// MarkStore persists the high-water mark of a [Generator]. The mark is a time
+// that is not before the timestamp of any key created by the generator.
+//
+// If a process restarts after the clock was set backwards, a generator that
+// uses a MarkStore will not create keys that duplicate keys created before the
+// restart.
+type MarkStore interface {
+	// Load returns the stored high-water mark. If no mark was stored, the
+	// zero time is returned without an error.
+	Load() (time.Time, error)
+
+	// Store persists the given high-water mark.
+	Store(time.Time) error
+}
+
+// WithMarkStore sets the store for the high-water mark of a [Generator].
+//
+// Before the first key is created, the generator loads the mark. It will only
+// create keys with a later timestamp. If the clock is behind the mark, the
+// policy of the generator determines its behaviour, as if all sequence numbers
+// of the mark were used.
+//
+// When the timestamp of a created key reaches the stored mark, a new mark is
+// stored, which is ahead of that timestamp by the given duration. A larger
+// duration means fewer store operations, but more unused timestamps after a
+// restart. The duration is at least one tick of the generator layout.
+func WithMarkStore(store MarkStore, ahead time.Duration) GeneratorOption {
+	return func(gen *Generator) {
+		gen.marks = store
+		gen.markAhead = ahead
+	}
+}
+
+// ErrMark signals an error while loading or storing a high-water mark.
+var ErrMark = errors.New("high-water mark")
+
+// loadMark retrieves the high-water mark from the store.
+//
+// gen.mx must be locked.
+func (gen *Generator) loadMark(maxSeq uint64) error {
+	mark, err := gen.marks.Load()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMark, err)
+	}
+	gen.markLoaded = true
+	if mark.IsZero() {
+		return nil
+	}
+	markTS := gen.layout.timestamp(mark)
+	gen.markTS = markTS
+	if markTS >= gen.lastTS {
+		gen.lastTS = markTS
+		gen.nextSeq = maxSeq
+	}
+	return nil
+}
+
+// checkpoint stores a new high-water mark, ahead of the last timestamp.
+//
+// gen.mx must be locked.
+func (gen *Generator) checkpoint() error {
+	markTS := gen.lastTS + max(int64(gen.markAhead/gen.layout.Tick()), 1)
+	if err := gen.marks.Store(gen.layout.timeOf(markTS)); err != nil {
+		return fmt.Errorf("%w: %w", ErrMark, err)
+	}
+	gen.markTS = markTS
+	return nil
+}
+
+// FileMarkStore is a [MarkStore] that stores the high-water mark in a file.
+// The file is written atomically.
+type FileMarkStore struct {
+	path string
+}
+
+// NewFileMarkStore returns a [MarkStore] that uses the file with the given
+// path.
+func NewFileMarkStore(path string) *FileMarkStore { return &FileMarkStore{path: path} }
+
+// Load reads the high-water mark from the file. If the file does not exist,
+// the zero time is returned.
+func (fms *FileMarkStore) Load() (time.Time, error) {
+	data, err := os.ReadFile(fms.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	var mark time.Time
+	if err = mark.UnmarshalText(bytes.TrimSpace(data)); err != nil {
+		return time.Time{}, fmt.Errorf("invalid mark in %q: %w", fms.path, err)
+	}
+	return mark, nil
+}
+
+// Store writes the high-water mark to the file.
+func (fms *FileMarkStore) Store(mark time.Time) error {
+	data, err := mark.UTC().MarshalText()
+	if err != nil {
+		return err
+	}
+	f, err := oso.SafeWrite(fms.path)
+	if err != nil {
+		return err
+	}
+	defer f.RollbackIfNeeded()
+
+	_, _ = f.Write(append(data, '\n'))
+	return f.Close()
+}
@@ -0,0 +1,171 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+// memMarkStore is a MarkStore for testing.
+type memMarkStore struct {
+	mark   time.Time
+	stores int
+	err    error
+}
+
+func (ms *memMarkStore) Load() (time.Time, error) { return ms.mark, ms.err }
+func (ms *memMarkStore) Store(mark time.Time) error {
+	if ms.err != nil {
+		return ms.err
+	}
+	ms.mark = mark
+	ms.stores++
+	return nil
+}
+
+func TestGeneratorMarkCheckpoint(t *testing.T) {
+	t.Parallel()
+	store := &memMarkStore{}
+	clock := newTestClock()
+	start := clock.now
+	generator := snow.New(0, snow.WithClock(clock), snow.WithMarkStore(store, time.Second))
+
+	_ = generator.Create(0)
+	if store.stores != 1 {
+		t.Errorf("mark should be stored once, but was stored %d times", store.stores)
+	}
+	if exp := start.Add(time.Second); !store.mark.Equal(exp) {
+		t.Errorf("mark %v expected, but got %v", exp, store.mark)
+	}
+	for range 999 {
+		clock.now = clock.now.Add(time.Millisecond)
+		_ = generator.Create(0)
+	}
+	if store.stores != 1 {
+		t.Errorf("mark should be stored once, but was stored %d times", store.stores)
+	}
+	clock.now = clock.now.Add(time.Millisecond)
+	_ = generator.Create(0)
+	if store.stores != 2 {
+		t.Errorf("mark should be stored twice, but was stored %d times", store.stores)
+	}
+	if exp := start.Add(2 * time.Second); !store.mark.Equal(exp) {
+		t.Errorf("mark %v expected, but got %v", exp, store.mark)
+	}
+
+	r, err := generator.Reserve(0, 2000<<22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := r.Last().Time().Add(time.Second); !store.mark.Equal(exp) {
+		t.Errorf("mark %v expected after reservation, but got %v", exp, store.mark)
+	}
+}
+
+func TestGeneratorMarkRestart(t *testing.T) {
+	t.Parallel()
+	for _, policy := range []snow.Policy{snow.PolicyBlock, snow.PolicyBorrow, snow.PolicyError} {
+		store := &memMarkStore{}
+		clock := newTestClock()
+		generator := snow.New(0, snow.WithClock(clock), snow.WithMarkStore(store, time.Minute))
+		lastKey := generator.Create(0)
+
+		// Restart after clock was set backwards.
+		mark := store.mark
+		clock.now = clock.now.Add(-time.Hour)
+		generator = snow.New(0, snow.WithClock(clock), snow.WithPolicy(policy), snow.WithMarkStore(store, time.Minute))
+		key, err := generator.CreateE(0)
+		if policy == snow.PolicyError {
+			if !errors.Is(err, snow.ErrSequenceExhausted) {
+				t.Errorf("error %v expected, but got %v / %v", snow.ErrSequenceExhausted, err, key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if key <= lastKey {
+			t.Errorf("policy %v: key does not increase: %v -> %v", policy, lastKey, key)
+		}
+		if !key.Time().After(mark) {
+			t.Errorf("policy %v: key time %v not after mark %v", policy, key.Time(), mark)
+		}
+	}
+}
+
+func TestGeneratorMarkError(t *testing.T) {
+	t.Parallel()
+	errStore := errors.New("store error")
+	store := &memMarkStore{err: errStore}
+	generator := snow.New(0, snow.WithClock(newTestClock()), snow.WithMarkStore(store, time.Second))
+	if key, err := generator.CreateE(0); !errors.Is(err, snow.ErrMark) || !errors.Is(err, errStore) {
+		t.Errorf("error %v expected, but got %v / %v", errStore, err, key)
+	}
+
+	store.err = nil
+	if _, err := generator.CreateE(0); err != nil {
+		t.Error(err)
+	}
+	store.err = errStore
+	generator.Create(0) // mark is ahead, no store needed
+}
+
+func TestFileMarkStore(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "mark")
+	store := snow.NewFileMarkStore(path)
+	mark, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mark.IsZero() {
+		t.Error("zero mark expected, but got", mark)
+	}
+
+	exp := time.Date(2026, time.October, 16, 12, 13, 14, 15000000, time.Local)
+	if err = store.Store(exp); err != nil {
+		t.Fatal(err)
+	}
+	if mark, err = store.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !mark.Equal(exp) {
+		t.Errorf("mark %v expected, but got %v", exp, mark)
+	}
+
+	clock := newTestClock()
+	genStore := snow.NewFileMarkStore(filepath.Join(t.TempDir(), "gen-mark"))
+	generator := snow.New(0, snow.WithClock(clock), snow.WithMarkStore(genStore, time.Second))
+	_ = generator.Create(0)
+	if mark, err = genStore.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if exp = clock.now.Add(time.Second); !mark.Equal(exp) {
+		t.Errorf("mark %v expected, but got %v", exp, mark)
+	}
+
+	if err = os.WriteFile(path, []byte("no time"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if mark, err = store.Load(); err == nil {
+		t.Error("error expected, but got", mark)
+	}
+}
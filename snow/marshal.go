@@ -0,0 +1,133 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// Ensure some interfaces.
+	_ encoding.TextMarshaler     = Key(0)
+	_ encoding.TextAppender      = Key(0)
+	_ encoding.TextUnmarshaler   = (*Key)(nil)
+	_ encoding.BinaryMarshaler   = Key(0)
+	_ encoding.BinaryUnmarshaler = (*Key)(nil)
+	_ json.Marshaler             = Key(0)
+	_ json.Unmarshaler           = (*Key)(nil)
+	_ sql.Scanner                = (*Key)(nil)
+	_ driver.Valuer              = Key(0)
+)
+
+// MarshalText returns the base-32 representation of the key, as produced by
+// [Key.String].
+func (key Key) MarshalText() ([]byte, error) { return key.AppendText(nil) }
+
+// AppendText appends the base-32 representation of the key, as produced by
+// [Key.String], to the given byte slice. Use [Key.AppendFixed] to append the
+// fixed-width representation.
+func (key Key) AppendText(b []byte) ([]byte, error) {
+	if key == 0 {
+		return append(b, '0'), nil
+	}
+	temp, tpos := key.reverseEncode()
+	for tpos > 0 {
+		tpos--
+		b = append(b, temp[tpos])
+	}
+	return b, nil
+}
+
+// UnmarshalText parses the given text into the key, see [Parse].
+func (key *Key) UnmarshalText(text []byte) error {
+	k, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*key = k
+	return nil
+}
+
+// MarshalJSON returns the key as a JSON string, containing the base-32
+// representation of the key.
+func (key Key) MarshalJSON() ([]byte, error) {
+	result, err := key.AppendText(append(make([]byte, 0, 16), '"'))
+	if err != nil {
+		return nil, err
+	}
+	return append(result, '"'), nil
+}
+
+// UnmarshalJSON parses a JSON string into the key. A JSON null value leaves
+// the key unchanged.
+func (key *Key) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("key must be a JSON string, but got: %s", data)
+	}
+	return key.UnmarshalText([]byte(s))
+}
+
+// MarshalBinary returns the key as 8 bytes in big-endian order.
+func (key Key) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(make([]byte, 0, 8), uint64(key)), nil
+}
+
+// UnmarshalBinary reads the key from 8 bytes in big-endian order.
+func (key *Key) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("binary key must have 8 bytes, but has %d", len(data))
+	}
+	*key = Key(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+// Value returns the key as a value to be stored in a database.
+//
+// The key is stored as an int64, because most databases do not support
+// unsigned 64 bit integers. All bits are retained, but keys with a timestamp
+// later than 2093 will be stored as a negative value.
+func (key Key) Value() (driver.Value, error) { return int64(key), nil }
+
+// Scan reads a value retrieved from a database into the key. Supported are
+// integer values, as written by [Key.Value], and base-32 strings. A NULL value
+// results in an [Invalid] key.
+func (key *Key) Scan(src any) error {
+	switch val := src.(type) {
+	case nil:
+		*key = Invalid
+	case int64:
+		*key = Key(val)
+	case string:
+		return key.UnmarshalText([]byte(val))
+	case []byte:
+		return key.UnmarshalText(val)
+	default:
+		return fmt.Errorf("unable to scan %T into key: %w", src, ErrScanType)
+	}
+	return nil
+}
+
+// ErrScanType signals that a database value of an unsupported type should be
+// scanned into a [Key].
+var ErrScanType = errors.New("unsupported type")
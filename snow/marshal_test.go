@@ -0,0 +1,208 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestKeyMarshalText(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		text string
+		exp  snow.Key
+		back string
+	}{
+		{"0", 0, "0"},
+		{"0000000000001", 1, "1"},
+		{"0E34NNFRTCQ15", 507945423712181285, "E34NNFRTCQ15"},
+		{"0e34nnfrtcq15", 507945423712181285, "E34NNFRTCQ15"},
+		{"ILO", 0b00001_00001_00000, "110"},
+		{"il-o", 0b00001_00001_00000, "110"},
+		{"F-zz-ZZZZZZZZ-zz", math.MaxUint64, "FZZZZZZZZZZZZ"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.text, func(t *testing.T) {
+			var key snow.Key
+			if err := key.UnmarshalText([]byte(tc.text)); err != nil {
+				t.Error(err)
+				return
+			}
+			if key != tc.exp {
+				t.Errorf("key %v expected, but got %v", tc.exp, key)
+			}
+			if parsed := snow.MustParse(tc.text); parsed != key {
+				t.Errorf("Parse and UnmarshalText differ: %v != %v", parsed, key)
+			}
+			got, err := key.MarshalText()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(got) != tc.back {
+				t.Errorf("%q expected, but got %q", tc.back, got)
+			}
+			if string(got) != key.String() {
+				t.Errorf("MarshalText and String differ: %q != %q", got, key.String())
+			}
+			if appended, _ := key.AppendText([]byte("k:")); string(appended) != "k:"+tc.back {
+				t.Errorf("AppendText should append %q, but got %q", tc.back, appended)
+			}
+		})
+	}
+
+	var key snow.Key
+	if err := key.UnmarshalText(nil); err != snow.ErrEmptyKey {
+		t.Errorf("error %v expected, but got %v", snow.ErrEmptyKey, err)
+	}
+	if err := key.UnmarshalText([]byte("0U")); err == nil {
+		t.Error("error expected, but got key", key)
+	}
+}
+
+func TestKeyMarshalJSON(t *testing.T) {
+	t.Parallel()
+	type data struct {
+		ID  snow.Key  `json:"id"`
+		Ref *snow.Key `json:"ref"`
+	}
+	ref := snow.Key(math.MaxUint64)
+	for _, d := range []data{
+		{0, nil},
+		{1, &ref},
+		{507945423712181285, nil},
+		{math.MaxUint64, &ref},
+	} {
+		js, err := json.Marshal(d)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		var got data
+		if err = json.Unmarshal(js, &got); err != nil {
+			t.Error(err)
+			continue
+		}
+		if got.ID != d.ID || (got.Ref == nil) != (d.Ref == nil) || (got.Ref != nil && *got.Ref != *d.Ref) {
+			t.Errorf("%s was unmarshaled to %v", js, got)
+		}
+	}
+
+	var got data
+	if err := json.Unmarshal([]byte(`{"id":"0e34-nnfr-tcq15","ref":"1O"}`), &got); err != nil {
+		t.Error(err)
+	} else if got.ID != 507945423712181285 || got.Ref == nil || *got.Ref != 32 {
+		t.Errorf("wrong key values: %v / %v", got.ID, got.Ref)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"\u0031","ref":"0e34\u002dnnfr-tcq15"}`), &got); err != nil {
+		t.Error(err)
+	} else if got.ID != 1 || got.Ref == nil || *got.Ref != 507945423712181285 {
+		t.Errorf("wrong escaped key values: %v / %v", got.ID, got.Ref)
+	}
+	if err := json.Unmarshal([]byte(`{"id":17}`), &got); err == nil {
+		t.Error("error expected for JSON number, but got", got)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"0<>"}`), &got); err == nil {
+		t.Error("error expected for wrong character, but got", got)
+	}
+}
+
+func TestKeyMarshalBinary(t *testing.T) {
+	t.Parallel()
+	for _, key := range []snow.Key{0, 1, 0x0102030405060708, math.MaxUint64} {
+		data, err := key.MarshalBinary()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if len(data) != 8 {
+			t.Errorf("8 bytes expected, but got %d: %v", len(data), data)
+			continue
+		}
+		var got snow.Key
+		if err = got.UnmarshalBinary(data); err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != key {
+			t.Errorf("key %v expected, but got %v", key, got)
+		}
+	}
+	data, _ := snow.Key(0x0102030405060708).MarshalBinary()
+	for i, b := range data {
+		if int(b) != i+1 {
+			t.Errorf("byte %d should be %d, but is %d", i, i+1, b)
+		}
+	}
+
+	var key snow.Key
+	if err := key.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Error("error expected, but got", key)
+	}
+}
+
+func TestKeySQL(t *testing.T) {
+	t.Parallel()
+	for _, key := range []snow.Key{0, 1, 507945423712181285, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		val, err := key.Value()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if _, isInt := val.(int64); !isInt {
+			t.Errorf("int64 value expected, but got %T", val)
+		}
+		var got snow.Key
+		if err = got.Scan(val); err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != key {
+			t.Errorf("key %v expected, but got %v", key, got)
+		}
+	}
+
+	var testcases = []struct {
+		src any
+		exp snow.Key
+	}{
+		{nil, snow.Invalid},
+		{int64(32), 32},
+		{"1O", 32},
+		{[]byte("1-l"), 33},
+	}
+	for _, tc := range testcases {
+		got := snow.Key(4711)
+		if err := got.Scan(tc.src); err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != tc.exp {
+			t.Errorf("scanning %v: %v expected, but got %v", tc.src, tc.exp, got)
+		}
+	}
+
+	var key snow.Key
+	if err := key.Scan(3.14); !errors.Is(err, snow.ErrScanType) {
+		t.Errorf("error %v expected, but got %v", snow.ErrScanType, err)
+	}
+	if err := key.Scan(""); err != snow.ErrEmptyKey {
+		t.Errorf("error %v expected, but got %v", snow.ErrEmptyKey, err)
+	}
+}
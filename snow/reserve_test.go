@@ -0,0 +1,118 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestReserve(t *testing.T) {
+	t.Parallel()
+	for _, appBits := range []uint{0, 7, snow.MaxAppBits} {
+		generator := snow.New(appBits, snow.WithClock(newTestClock()))
+		appID := generator.MaxAppID()
+		maxSeq := 1 << (22 - appBits)
+
+		before := generator.Create(appID)
+		for _, n := range []int{1, 2, maxSeq - 1, maxSeq, maxSeq + 1, 3*maxSeq + 7} {
+			r, err := generator.Reserve(appID, n)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if got := r.Len(); got != n {
+				t.Errorf("reservation should contain %d keys, but has %d", n, got)
+			}
+			keys := slices.Collect(r.All())
+			if len(keys) != n {
+				t.Errorf("%d keys expected, but got %d", n, len(keys))
+				continue
+			}
+			if keys[0] != r.First() || keys[n-1] != r.Last() {
+				t.Errorf("first/last key should be %v/%v, but got %v/%v", keys[0], keys[n-1], r.First(), r.Last())
+			}
+			lastKey := before
+			for i, key := range keys {
+				if key <= lastKey {
+					t.Errorf("key does not increase: %v -> %v", lastKey, key)
+					break
+				}
+				if got := generator.AppID(key); got != appID {
+					t.Errorf("application value %d expected, but got %d", appID, got)
+					break
+				}
+				if got := r.At(i); got != key {
+					t.Errorf("key %d should be %v, but got %v", i, key, got)
+					break
+				}
+				lastKey = key
+			}
+			after := generator.Create(appID)
+			if after <= lastKey {
+				t.Errorf("key after reservation does not increase: %v -> %v", lastKey, after)
+			}
+			before = after
+		}
+	}
+}
+
+func TestReserveSpan(t *testing.T) {
+	t.Parallel()
+	clock := newTestClock()
+	generator := snow.New(snow.MaxAppBits, snow.WithClock(clock))
+	r, err := generator.Reserve(3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := clock.now.Add(2 * time.Millisecond); !r.Last().Time().Equal(exp) {
+		t.Errorf("last key should have time %v, but got %v", exp, r.Last().Time())
+	}
+	if got := generator.KeySeq(r.Last()); got != 1 {
+		t.Errorf("last key should have sequence 1, but got %d", got)
+	}
+	if got := r.At(10); got != snow.Invalid {
+		t.Error("key out of range should be invalid, but got", got)
+	}
+	if got := r.At(-1); got != snow.Invalid {
+		t.Error("key out of range should be invalid, but got", got)
+	}
+}
+
+func TestReserveErrors(t *testing.T) {
+	t.Parallel()
+	generator := snow.New(1)
+	if r, err := generator.Reserve(0, 0); err != nil || r.Len() != 0 || r.First() != snow.Invalid || r.Last() != snow.Invalid {
+		t.Errorf("empty reservation expected, but got %v / %v", r, err)
+	}
+	if r, err := generator.Reserve(0, -1); err == nil {
+		t.Error("error expected, but got", r)
+	}
+	if r, err := generator.Reserve(2, 1); !errors.Is(err, snow.ErrAppID) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrAppID, err, r)
+	}
+
+	clock := newTestClock()
+	generator = snow.New(snow.MaxAppBits, snow.WithClock(clock))
+	if r, err := generator.Reserve(0, 1<<62); !errors.Is(err, snow.ErrTimestamp) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrTimestamp, err, r)
+	}
+	if key := generator.Create(0); key.Time().After(clock.now) || generator.KeySeq(key) != 0 {
+		t.Errorf("failed reservation changed generator: %v / %d", key.Time(), generator.KeySeq(key))
+	}
+}
@@ -16,6 +16,7 @@
 package snow
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"strings"
@@ -41,6 +42,9 @@ import (
 // used, the less bits are available for the sequence number. An application
 // can use the bits to store the number of a database table, or the number of a
 // computing node.
+//
+// This is the default layout of a key. Other epochs, timestamp sizes, and
+// timestamp resolutions can be configured with a [Layout].
 type Key uint64
 
 // Invalid is the default invalid key.
@@ -49,15 +53,14 @@ const Invalid Key = 0
 const (
 	timestampBits = 42
 	randomBits    = 22
-
-	maxTimeStamp = int64(1<<timestampBits - 1)
 )
 
 // MaxAppBits states the maximum number of bits reserved for the application
 // defined part of the key.
 const MaxAppBits = 20
 
-// Parse will parse a non-empty string into an external key.
+// Parse will parse a non-empty string into an external key. It accepts the
+// results of [Key.String], [Key.Format], and [Key.FormatFixed].
 func Parse(s string) (Key, error) {
 	if s == "" {
 		return Invalid, ErrEmptyKey
@@ -72,13 +75,13 @@ func Parse(s string) (Key, error) {
 			val := decode32map[ch-'0']
 			if 0 <= val && val <= 31 {
 				if result&0xF800000000000000 != 0 {
-					return Invalid, fmt.Errorf("does not fit in uint64: %q / %x", s, uint64(result))
+					return Invalid, &ParseError{Input: s, Pos: i, Err: ErrOverflow}
 				}
 				result = (result << 5) | Key(val)
 				continue
 			}
 		}
-		return result, fmt.Errorf("non base-32 character %c/%v found", ch, ch)
+		return result, &ParseError{Input: s, Pos: i, Err: ErrBadChar}
 	}
 	return result, nil
 }
@@ -86,6 +89,51 @@ func Parse(s string) (Key, error) {
 // ErrEmptyKey signals the an empty string was used to parse it into a [Key].
 var ErrEmptyKey = errors.New("empty key")
 
+// Errors that are wrapped by a [ParseError] to signal the cause of the error.
+var (
+	// ErrBadChar signals an invalid character, e.g. a non base-32 character.
+	ErrBadChar = errors.New("bad character")
+
+	// ErrOverflow signals that the string does not fit in a [Key].
+	ErrOverflow = errors.New("overflow")
+
+	// ErrChecksum signals that the check symbol does not match the key.
+	ErrChecksum = errors.New("checksum mismatch")
+
+	// ErrLength signals a string of the wrong length for a fixed-width
+	// encoding.
+	ErrLength = errors.New("wrong length")
+)
+
+// ParseError is returned if a string could not be parsed into a [Key].
+//
+// Use errors.Is to check for the cause of the error: [ErrBadChar],
+// [ErrOverflow], [ErrChecksum], or [ErrLength].
+type ParseError struct {
+	Input    string // The string that should be parsed.
+	Pos      int    // Byte position within Input, where the error was detected.
+	Err      error  // Cause of the error.
+	Encoding string // Name of the expected encoding; empty means "base-32".
+}
+
+func (e *ParseError) Error() string {
+	switch e.Err {
+	case ErrBadChar:
+		ch := e.Input[e.Pos]
+		return fmt.Sprintf("non %s character %c/%v found", cmp.Or(e.Encoding, "base-32"), ch, ch)
+	case ErrOverflow:
+		return fmt.Sprintf("does not fit in uint64: %q", e.Input)
+	case ErrChecksum:
+		return fmt.Sprintf("checksum mismatch: %q", e.Input)
+	case ErrLength:
+		return fmt.Sprintf("%s has wrong length %d: %q", cmp.Or(e.Encoding, "base-32"), len(e.Input), e.Input)
+	}
+	return fmt.Sprintf("%v: %q", e.Err, e.Input)
+}
+
+// Unwrap returns the cause of the error.
+func (e *ParseError) Unwrap() error { return e.Err }
+
 // MustParse parses the string into an external key, and panics if that is not possible.
 func MustParse(s string) Key {
 	key, err := Parse(s)
@@ -109,7 +157,8 @@ var decode32map = [...]int8{
 	22, 23, 24, 25, 26, -1, 27, 28, 29, 30, 31, -1, -1, -1, -1, -1, // 0x70 .. 0x7f
 }
 
-// Time returns the timestamp value, when the key was generated.
+// Time returns the timestamp value, when the key was generated. It assumes
+// the default layout, use [Layout.Time] for other layouts.
 func (key Key) Time() time.Time {
 	return time.UnixMilli(int64(key>>randomBits) + epochAdjust)
 }
@@ -129,6 +178,33 @@ func (key Key) String() string {
 	return string(result[:tpos])
 }
 
+// FixedLen is the number of characters of the fixed-width representation of
+// a key, as returned by [Key.FormatFixed].
+const FixedLen = 13
+
+// FormatFixed returns a base-32 representation of the key with exactly
+// [FixedLen] characters, padded with leading zeros. In contrast to
+// [Key.String], the byte-wise order of the results is the same as the
+// numeric order of the keys. Therefore, it is suitable for file names or
+// other text keys that are sorted lexicographically.
+//
+// The result is accepted by [Parse].
+func (key Key) FormatFixed() string {
+	return string(key.AppendFixed(make([]byte, 0, FixedLen)))
+}
+
+// AppendFixed appends the fixed-width representation of the key, as returned
+// by [Key.FormatFixed], to the given byte slice.
+func (key Key) AppendFixed(b []byte) []byte {
+	var result [FixedLen]byte
+	u64 := uint64(key)
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = base32chars[u64%32]
+		u64 >>= 5
+	}
+	return append(b, result[:]...)
+}
+
 var sepMask = []uint16{
 	0b0000000000000, // 0  = "ABCDEFGHJKMNP" (sentinel)
 	0b0111111111111, // 1  = "A-B-C-D-E-F-G-H-J-K-M-N-P"
@@ -212,26 +288,118 @@ func (key Key) reverseEncode() ([13]byte, int) {
 const base32chars = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
 
 // Generator is a generator for unique keys as int64.
+//
+// The zero value is a generator without application defined bits, that uses
+// the system clock and the [PolicyBlock].
 type Generator struct {
-	mx      sync.Mutex // Protects the next two fields
-	lastTS  int64      // Last timestamp
+	mx      sync.Mutex // Protects the next three fields, and the mark fields
+	lastTS  int64      // Last timestamp, in ticks of the layout
+	wallTS  int64      // Last timestamp read from the clock, in ticks
 	nextSeq uint64     // Next sequence number for lastTS
-	appBits uint       // number of bits for application use. range: 0-MaxAppBits
-	appMax  uint       // 1 << appBits (if appBits > 0; else: 0)
+	layout  Layout     // Bit layout of the created keys
+	clock   Clock      // Source of the current time; nil means system clock
+	policy  Policy     // How to handle exhausted sequences and clock jumps
+
+	marks      MarkStore     // Persistent storage of the high-water mark, if not nil
+	markAhead  time.Duration // Stored mark is ahead of lastTS by this duration
+	markTS     int64         // Last stored high-water mark, in ticks
+	markLoaded bool          // High-water mark was loaded from the store
+
+	lease *Lease // Lease of the application value, if not nil
+}
+
+// GeneratorOption allows to customize a [Generator], when it is created.
+type GeneratorOption func(*Generator)
+
+// WithClock sets the clock that is used by a [Generator] to retrieve the
+// current time. Typically used for testing.
+func WithClock(clock Clock) GeneratorOption {
+	return func(gen *Generator) { gen.clock = clock }
+}
+
+// WithLayout sets the layout of the keys created by a [Generator]. It
+// overrides the number of application bits given to [New].
+func WithLayout(layout Layout) GeneratorOption {
+	return func(gen *Generator) { gen.layout = layout }
+}
+
+// WithPolicy sets the policy of a [Generator], that determines its behaviour
+// if the sequence numbers for one millisecond are exhausted, or if the clock
+// moves backwards.
+func WithPolicy(policy Policy) GeneratorOption {
+	return func(gen *Generator) { gen.policy = policy }
 }
 
 // New creates a new key generator with a given number of bits for
 // application use.
-func New(appBits uint) *Generator {
+func New(appBits uint, opts ...GeneratorOption) *Generator {
 	if appBits > MaxAppBits {
 		panic(fmt.Sprintf("key generator need too many bits (max %d): %v", appBits, MaxAppBits))
 	}
-	return &Generator{
-		appBits: appBits,
-		appMax:  1 << appBits,
+	gen := &Generator{layout: NewLayout(appBits)}
+	for _, opt := range opts {
+		opt(gen)
 	}
+	return gen
 }
 
+// Clock is the source of time for a [Generator].
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Sleep pauses the current goroutine for at least the given duration.
+	Sleep(time.Duration)
+}
+
+// SystemClock is the [Clock] of the operating system.
+type SystemClock struct{}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// Sleep pauses the current goroutine.
+func (SystemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// Policy determines how a [Generator] behaves, if all sequence numbers of the
+// current millisecond are used, or if the clock moves backwards.
+//
+// For a [Layout] with a coarser resolution, "millisecond" means one tick of
+// the layout.
+type Policy uint8
+
+// Constants for Policy.
+const (
+	// PolicyBlock waits until the clock reaches the next millisecond, or
+	// until it reaches the last used millisecond again.
+	PolicyBlock Policy = iota
+
+	// PolicyBorrow does not wait, but uses the next logical millisecond. If
+	// the clock moves backwards, the last used millisecond is used. Created
+	// keys may contain a timestamp from the near future.
+	PolicyBorrow
+
+	// PolicyError does not wait, but lets [Generator.CreateE] return an error,
+	// either [ErrSequenceExhausted] or [ErrClockBackwards].
+	PolicyError
+)
+
+// Errors returned by [Generator.CreateE].
+var (
+	// ErrAppID signals an application value that is too large.
+	ErrAppID = errors.New("application value out of range")
+
+	// ErrSequenceExhausted signals that all sequence numbers of the current
+	// millisecond were already used.
+	ErrSequenceExhausted = errors.New("sequence numbers exhausted")
+
+	// ErrClockBackwards signals that the clock moved backwards.
+	ErrClockBackwards = errors.New("clock moved backwards")
+
+	// ErrTimestamp signals that the current time cannot be stored in a key.
+	ErrTimestamp = errors.New("timestamp out of range")
+)
+
 // epochAdjust is used to make the timestamp values smaller, so they better fit
 // in 42 bits.
 //
@@ -239,52 +407,154 @@ func New(appBits uint) *Generator {
 const epochAdjust int64 = 1717200000000
 
 // Create generates a new key with the given application data.
+//
+// It panics, if [Generator.CreateE] returns an error.
 func (gen *Generator) Create(appID uint) Key {
-	if appID > 0 && appID >= gen.appMax {
-		panic(fmt.Errorf("application value out of range: %v (max: %v)", appID, gen.appMax))
+	key, err := gen.CreateE(appID)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// CreateE generates a new key with the given application data, or returns
+// an error.
+//
+// Depending on the policy of the generator, it may wait until the key can be
+// created, see [Policy].
+func (gen *Generator) CreateE(appID uint) (Key, error) { return gen.reserve(appID, 1) }
+
+// reserve allocates n consecutive keys and returns the first one.
+func (gen *Generator) reserve(appID uint, n uint64) (Key, error) {
+	layout := gen.layout
+	if maxID := layout.MaxAppID(); appID > maxID {
+		return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrAppID, appID, maxID)
 	}
+	clock := gen.clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	maxSeq := uint64(1) << layout.SeqBits()
+	maxTS := layout.maxTimestamp()
 	for {
-		milli := int64(time.Now().UnixMilli())
-		var seq uint64
+		now := clock.Now()
+		if lease := gen.lease; lease != nil && !lease.ValidAt(now) {
+			return Invalid, fmt.Errorf("%w: %v", ErrLeaseExpired, lease.Expires())
+		}
+		ts := layout.timestamp(now)
+		if ts < 0 {
+			return Invalid, fmt.Errorf("%w: %v (min: 0)", ErrTimestamp, ts)
+		}
+		if ts > maxTS {
+			return Invalid, fmt.Errorf("%w: %v (max: %v)", ErrTimestamp, ts, maxTS)
+		}
 
 		gen.mx.Lock()
-		if milli > gen.lastTS {
-			gen.lastTS = milli
-			gen.nextSeq = 1
-			seq = 0
-		} else {
-			seq = gen.nextSeq
-			gen.nextSeq++
-		}
+		ts, seq, wait, err := gen.allocate(ts, n, maxSeq, maxTS)
 		gen.mx.Unlock()
 
-		if seq < (1 << (randomBits - gen.appBits)) {
-			ts := milli - epochAdjust
-			if ts > maxTimeStamp {
-				panic(fmt.Sprintf("timestamp %v exceeds largest possible value %v", ts, maxTimeStamp))
-			}
+		if err != nil {
+			return Invalid, err
+		}
+		if wait > 0 {
+			clock.Sleep(wait)
+			continue
+		}
+		return layout.key(ts, appID, seq), nil
+	}
+}
 
-			// 42bit=ts, kg.intBits=appId, 22-kg.intBits=seq
-			k := (uint64(ts) << randomBits) | (uint64(appID) << (randomBits - gen.appBits)) | seq
-			return Key(k)
+// allocate calculates timestamp and sequence number of the first of n keys,
+// and updates the generator state accordingly. If the caller must wait, a
+// positive duration is returned. If an error is returned, the generator state
+// is left unchanged.
+//
+// gen.mx must be locked.
+func (gen *Generator) allocate(ts int64, n, maxSeq uint64, maxTS int64) (_ int64, _ uint64, _ time.Duration, err error) {
+	if gen.marks != nil && !gen.markLoaded {
+		if err = gen.loadMark(maxSeq); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	wallTS, lastTS, nextSeq := gen.wallTS, gen.lastTS, gen.nextSeq
+	defer func() {
+		if err != nil {
+			gen.wallTS, gen.lastTS, gen.nextSeq = wallTS, lastTS, nextSeq
 		}
+	}()
 
-		time.Sleep(1 * time.Millisecond)
+	ts, seq, wait, err := gen.next(ts, maxSeq, gen.layout.Tick())
+	if err != nil || wait > 0 {
+		return 0, 0, wait, err
+	}
+	if ts > maxTS { // Borrowed from the future
+		return 0, 0, 0, fmt.Errorf("%w: %v (max: %v)", ErrTimestamp, ts, maxTS)
 	}
+	if n > 1 {
+		end := seq + n - 1
+		lastTS := ts + int64(end/maxSeq)
+		if lastTS > maxTS || lastTS < ts {
+			return 0, 0, 0, fmt.Errorf("%w: %v (max: %v)", ErrTimestamp, lastTS, maxTS)
+		}
+		gen.lastTS = lastTS
+		gen.nextSeq = end%maxSeq + 1
+	}
+	if gen.marks != nil && gen.lastTS >= gen.markTS {
+		if err = gen.checkpoint(); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return ts, seq, 0, nil
 }
 
-// AppID returns the application defined part of the key.
-func (gen *Generator) AppID(key Key) uint {
-	if appBits := gen.appBits; appBits > 0 {
-		return uint((key & 0x3fffff) >> (randomBits - appBits))
+// next calculates timestamp and sequence number for the next key, based on
+// the current timestamp of the clock. If the caller must wait, a positive
+// duration is returned.
+//
+// gen.mx must be locked.
+func (gen *Generator) next(ts int64, maxSeq uint64, tick time.Duration) (int64, uint64, time.Duration, error) {
+	if ts < gen.wallTS {
+		switch gen.policy {
+		case PolicyBlock:
+			return 0, 0, time.Duration(gen.wallTS-ts) * tick, nil
+		case PolicyError:
+			return 0, 0, 0, fmt.Errorf("%w: %v -> %v", ErrClockBackwards, gen.wallTS, ts)
+		}
+		ts = gen.wallTS
+	}
+	gen.wallTS = ts
+
+	if ts > gen.lastTS {
+		gen.lastTS = ts
+		gen.nextSeq = 1
+		return ts, 0, 0, nil
+	}
+	// The zero state treats sequence number 0 of timestamp 0 as used, so that
+	// the key Invalid is never created.
+	if seq := max(gen.nextSeq, 1); seq < maxSeq {
+		gen.nextSeq = seq + 1
+		return gen.lastTS, seq, 0, nil
+	}
+
+	switch gen.policy {
+	case PolicyBlock:
+		return 0, 0, tick, nil
+	case PolicyError:
+		return 0, 0, 0, fmt.Errorf("%w: %v", ErrSequenceExhausted, gen.lastTS)
 	}
-	return 0
+	gen.lastTS++
+	gen.nextSeq = 1
+	return gen.lastTS, 0, 0, nil
 }
 
+// Layout returns the bit layout of the keys created by the generator.
+func (gen *Generator) Layout() Layout { return gen.layout }
+
+// AppID returns the application defined part of the key.
+func (gen *Generator) AppID(key Key) uint { return gen.layout.AppID(key) }
+
 // KeySeq returns the sequence number of the given key.
-func (gen *Generator) KeySeq(key Key) uint {
-	return uint((key & 0x3fffff)) & (1<<(randomBits-gen.appBits) - 1)
-}
+func (gen *Generator) KeySeq(key Key) uint { return gen.layout.Seq(key) }
 
 // MaxAppID returns the maximum application ID for `gen.Create(appID)`.
-func (gen *Generator) MaxAppID() uint { return gen.appMax - 1 }
+func (gen *Generator) MaxAppID() uint { return gen.layout.MaxAppID() }
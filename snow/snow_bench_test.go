@@ -55,3 +55,65 @@ func BenchmarkSnowflakeFormatNoSep(b *testing.B) {
 		_ = generator.Create(0).Format(4, "")
 	}
 }
+
+const numBulkKeys = 10000
+
+func BenchmarkSnowflakeCreateBulk(b *testing.B) {
+	var generator snow.Generator
+	for b.Loop() {
+		for range numBulkKeys {
+			generator.Create(0)
+		}
+	}
+}
+
+func BenchmarkSnowflakeReserveBulk(b *testing.B) {
+	var generator snow.Generator
+	for b.Loop() {
+		r, err := generator.Reserve(0, numBulkKeys)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for range r.All() {
+		}
+	}
+}
+
+func BenchmarkSnowflakeReserveBulkX(b *testing.B) {
+	bits := 7
+	generator := snow.New(uint(bits))
+	key := uint((1 << bits) - 1)
+	for b.Loop() {
+		r, err := generator.Reserve(key, numBulkKeys)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for range r.All() {
+		}
+	}
+}
+
+func BenchmarkSnowflakeParallel(b *testing.B) {
+	var generator snow.Generator
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			generator.Create(0)
+		}
+	})
+}
+
+func BenchmarkSnowflakeAtomic(b *testing.B) {
+	var generator snow.AtomicGenerator
+	for b.Loop() {
+		generator.Create(0)
+	}
+}
+
+func BenchmarkSnowflakeAtomicParallel(b *testing.B) {
+	var generator snow.AtomicGenerator
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			generator.Create(0)
+		}
+	})
+}
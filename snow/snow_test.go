@@ -14,11 +14,14 @@
 package snow_test
 
 import (
+	"cmp"
+	"errors"
 	"math"
 	"math/rand"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"t73f.de/r/zero/snow"
 )
@@ -104,6 +107,52 @@ func TestKeyFormat(t *testing.T) {
 	}
 }
 
+func TestKeyFormatFixed(t *testing.T) {
+	t.Parallel()
+	var testcases = []struct {
+		key snow.Key
+		exp string
+	}{
+		{0, "0000000000000"},
+		{1, "0000000000001"},
+		{31, "000000000000Z"},
+		{32, "0000000000010"},
+		{math.MaxInt64, "7ZZZZZZZZZZZZ"},
+		{math.MaxUint64, "FZZZZZZZZZZZZ"},
+	}
+	for _, tc := range testcases {
+		got := tc.key.FormatFixed()
+		if got != tc.exp {
+			t.Errorf("%d.FormatFixed() should be %q, but got %q", tc.key, tc.exp, got)
+		}
+		if key, err := snow.Parse(got); err != nil || key != tc.key {
+			t.Errorf("Parse(%q) should be %d, but got %d (err: %v)", got, tc.key, key, err)
+		}
+	}
+	if got := string(snow.Key(32).AppendFixed([]byte("k-"))); got != "k-0000000000010" {
+		t.Errorf("AppendFixed should append, but got %q", got)
+	}
+}
+
+func FuzzKeyFixedOrder(f *testing.F) {
+	f.Add(uint64(0), uint64(1))
+	f.Add(uint64(31), uint64(32))
+	f.Add(uint64(math.MaxInt64), uint64(math.MaxUint64))
+	f.Fuzz(func(t *testing.T, a, b uint64) {
+		keyA, keyB := snow.Key(a), snow.Key(b)
+		fixA, fixB := keyA.FormatFixed(), keyB.FormatFixed()
+		if len(fixA) != snow.FixedLen || len(fixB) != snow.FixedLen {
+			t.Fatalf("fixed length %d expected, but got %q and %q", snow.FixedLen, fixA, fixB)
+		}
+		if got, exp := strings.Compare(fixA, fixB), cmp.Compare(keyA, keyB); got != exp {
+			t.Errorf("order of %q and %q is %d, but order of keys %d and %d is %d", fixA, fixB, got, a, b, exp)
+		}
+		if key, err := snow.Parse(fixA); err != nil || key != keyA {
+			t.Errorf("Parse(%q) should be %d, but got %d (err: %v)", fixA, keyA, key, err)
+		}
+	})
+}
+
 func TestGenerator(t *testing.T) {
 	t.Parallel()
 	var generator snow.Generator
@@ -281,3 +330,216 @@ func TestKeySeq(t *testing.T) {
 		lastSeqno = seqno
 	}
 }
+
+// testClock is a clock for testing, where time only changes if requested.
+type testClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (tc *testClock) Now() time.Time { return tc.now }
+func (tc *testClock) Sleep(d time.Duration) {
+	tc.slept += d
+	tc.now = tc.now.Add(d)
+}
+
+func TestGeneratorClock(t *testing.T) {
+	t.Parallel()
+	clock := newTestClock()
+	generator := snow.New(0, snow.WithClock(clock))
+	for i := range 10 {
+		key := generator.Create(0)
+		if got := key.Time(); !got.Equal(clock.now) {
+			t.Errorf("time %v expected, but got %v", clock.now, got)
+		}
+		if got := generator.KeySeq(key); got != uint(i) {
+			t.Errorf("sequence %d expected, but got %d", i, got)
+		}
+	}
+}
+
+func TestGeneratorAtEpoch(t *testing.T) {
+	t.Parallel()
+	clock := newTestClock()
+	layout, err := snow.NewLayoutWith(snow.LayoutConfig{Epoch: clock.now, Tick: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generator := snow.New(0, snow.WithClock(clock), snow.WithLayout(layout))
+	lastKey := snow.Invalid
+	for range 3 {
+		key, errKey := generator.CreateE(0)
+		if errKey != nil {
+			t.Fatal(errKey)
+		}
+		if key <= lastKey {
+			t.Errorf("key does not increase: %v -> %v", lastKey, key)
+		}
+		lastKey = key
+	}
+}
+
+func TestGeneratorPolicyExhausted(t *testing.T) {
+	t.Parallel()
+	const appBits = snow.MaxAppBits
+	const maxSeq = 4 // 1 << (22 - appBits)
+	start := newTestClock().now
+
+	t.Run("block", func(t *testing.T) {
+		clock := newTestClock()
+		generator := snow.New(appBits, snow.WithClock(clock))
+		for range maxSeq {
+			_ = generator.Create(1)
+		}
+		key := generator.Create(1)
+		if clock.slept != time.Millisecond {
+			t.Errorf("should sleep 1ms, but slept %v", clock.slept)
+		}
+		if exp := start.Add(time.Millisecond); !key.Time().Equal(exp) {
+			t.Errorf("time %v expected, but got %v", exp, key.Time())
+		}
+	})
+
+	t.Run("borrow", func(t *testing.T) {
+		clock := newTestClock()
+		generator := snow.New(appBits, snow.WithClock(clock), snow.WithPolicy(snow.PolicyBorrow))
+		var lastKey snow.Key
+		for i := range 3 * maxSeq {
+			key := generator.Create(1)
+			if key <= lastKey {
+				t.Errorf("key does not increase: %v -> %v", lastKey, key)
+			}
+			lastKey = key
+			if exp := start.Add(time.Duration(i/maxSeq) * time.Millisecond); !key.Time().Equal(exp) {
+				t.Errorf("time %v expected, but got %v", exp, key.Time())
+			}
+			if got := generator.KeySeq(key); got != uint(i%maxSeq) {
+				t.Errorf("sequence %d expected, but got %d", i%maxSeq, got)
+			}
+		}
+		if clock.slept != 0 {
+			t.Error("should not sleep, but slept", clock.slept)
+		}
+
+		// Clock reaches borrowed millisecond
+		clock.now = clock.now.Add(time.Millisecond)
+		if key := generator.Create(1); key <= lastKey {
+			t.Errorf("key does not increase: %v -> %v", lastKey, key)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		clock := newTestClock()
+		generator := snow.New(appBits, snow.WithClock(clock), snow.WithPolicy(snow.PolicyError))
+		for range maxSeq {
+			if _, err := generator.CreateE(1); err != nil {
+				t.Error(err)
+			}
+		}
+		if key, err := generator.CreateE(1); !errors.Is(err, snow.ErrSequenceExhausted) {
+			t.Errorf("error %v expected, but got %v / %v", snow.ErrSequenceExhausted, err, key)
+		}
+		clock.now = clock.now.Add(time.Millisecond)
+		if _, err := generator.CreateE(1); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestGeneratorPolicyBackwards(t *testing.T) {
+	t.Parallel()
+	const jump = 5 * time.Millisecond
+
+	t.Run("block", func(t *testing.T) {
+		clock := newTestClock()
+		generator := snow.New(0, snow.WithClock(clock))
+		first := generator.Create(0)
+		clock.now = clock.now.Add(-jump)
+		if key := generator.Create(0); key <= first {
+			t.Errorf("key does not increase: %v -> %v", first, key)
+		}
+		if clock.slept != jump {
+			t.Errorf("should sleep %v, but slept %v", jump, clock.slept)
+		}
+	})
+
+	t.Run("borrow", func(t *testing.T) {
+		clock := newTestClock()
+		generator := snow.New(0, snow.WithClock(clock), snow.WithPolicy(snow.PolicyBorrow))
+		first := generator.Create(0)
+		clock.now = clock.now.Add(-jump)
+		key := generator.Create(0)
+		if key <= first {
+			t.Errorf("key does not increase: %v -> %v", first, key)
+		}
+		if !key.Time().Equal(first.Time()) {
+			t.Errorf("time %v expected, but got %v", first.Time(), key.Time())
+		}
+		if clock.slept != 0 {
+			t.Error("should not sleep, but slept", clock.slept)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		clock := newTestClock()
+		generator := snow.New(0, snow.WithClock(clock), snow.WithPolicy(snow.PolicyError))
+		_ = generator.Create(0)
+		clock.now = clock.now.Add(-jump)
+		if key, err := generator.CreateE(0); !errors.Is(err, snow.ErrClockBackwards) {
+			t.Errorf("error %v expected, but got %v / %v", snow.ErrClockBackwards, err, key)
+		}
+	})
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	t.Parallel()
+	generator := snow.New(1)
+	if key, err := generator.CreateE(2); !errors.Is(err, snow.ErrAppID) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrAppID, err, key)
+	}
+
+	clock := newTestClock()
+	clock.now = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	generator = snow.New(0, snow.WithClock(clock))
+	if key, err := generator.CreateE(0); !errors.Is(err, snow.ErrTimestamp) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrTimestamp, err, key)
+	}
+	clock.now = time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if key, err := generator.CreateE(0); !errors.Is(err, snow.ErrTimestamp) {
+		t.Errorf("error %v expected, but got %v / %v", snow.ErrTimestamp, err, key)
+	}
+}
+
+func TestGeneratorTimestampRecovery(t *testing.T) {
+	t.Parallel()
+	for _, policy := range []snow.Policy{snow.PolicyBlock, snow.PolicyBorrow, snow.PolicyError} {
+		clock := newTestClock()
+		store := &memMarkStore{}
+		generator := snow.New(0, snow.WithClock(clock), snow.WithPolicy(policy), snow.WithMarkStore(store, time.Second))
+		first := generator.Create(0)
+		mark := store.mark
+
+		normal := clock.now
+		clock.now = time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if key, err := generator.CreateE(0); !errors.Is(err, snow.ErrTimestamp) {
+			t.Errorf("%v: error %v expected, but got %v / %v", policy, snow.ErrTimestamp, err, key)
+		}
+		if !store.mark.Equal(mark) {
+			t.Errorf("%v: mark changed to %v", policy, store.mark)
+		}
+
+		clock.now = normal
+		key, err := generator.CreateE(0)
+		if err != nil {
+			t.Errorf("%v: generator did not recover: %v", policy, err)
+			continue
+		}
+		if key <= first || !key.Time().Equal(normal) || clock.slept != 0 {
+			t.Errorf("%v: unexpected key %v at %v after sleeping %v", policy, key, key.Time(), clock.slept)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Validator checks whether keys are plausible for a given [Layout], e.g. if
+// keys are retrieved from untrusted sources, like URLs.
+//
+// A key is always rejected, if it is the [Invalid] key. All other checks must
+// be enabled by a [ValidatorOption].
+type Validator struct {
+	layout    Layout
+	clock     Clock // nil means system clock
+	notBefore int64 // Minimum timestamp, in ticks of the layout
+	future    bool  // Check for keys from the future
+	tolerance time.Duration
+	maxAppID  uint
+}
+
+// ValidatorOption configures a [Validator].
+type ValidatorOption func(*Validator)
+
+// NewValidator creates a new validator for keys of the given layout.
+func NewValidator(layout Layout, opts ...ValidatorOption) *Validator {
+	v := &Validator{layout: layout, maxAppID: layout.MaxAppID()}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validator creates a new validator for keys created by the generator. It
+// uses the layout and the clock of the generator.
+func (gen *Generator) Validator(opts ...ValidatorOption) *Validator {
+	return NewValidator(gen.layout, append([]ValidatorOption{ValidatorClock(gen.clock)}, opts...)...)
+}
+
+// RejectBefore rejects keys that were created before the given time, e.g. the
+// start date of a service.
+func RejectBefore(t time.Time) ValidatorOption {
+	return func(v *Validator) { v.notBefore = v.layout.timestamp(t) }
+}
+
+// RejectFuture rejects keys that were created after the current time of the
+// validator clock, allowing the given tolerance for clock differences.
+func RejectFuture(tolerance time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.future = true
+		v.tolerance = tolerance
+	}
+}
+
+// RejectAppIDAbove rejects keys whose application defined value is greater
+// than the given value, e.g. the number of replicas of a service.
+func RejectAppIDAbove(maxAppID uint) ValidatorOption {
+	return func(v *Validator) { v.maxAppID = maxAppID }
+}
+
+// ValidatorClock sets the clock used to check for keys from the future.
+func ValidatorClock(clock Clock) ValidatorOption {
+	return func(v *Validator) { v.clock = clock }
+}
+
+// Check identifies a check of a [Validator].
+type Check uint8
+
+// Constants for all checks of a [Validator].
+const (
+	_           Check = iota
+	CheckZero         // Key is the Invalid key.
+	CheckBefore       // Key was created before the configured time.
+	CheckFuture       // Key was created in the future.
+	CheckAppID        // Application defined value is out of range.
+)
+
+var checkNames = [...]string{
+	CheckZero:   "invalid key",
+	CheckBefore: "timestamp too early",
+	CheckFuture: "timestamp in the future",
+	CheckAppID:  "application value out of range",
+}
+
+// String returns a description of the check.
+func (c Check) String() string {
+	if int(c) < len(checkNames) && checkNames[c] != "" {
+		return checkNames[c]
+	}
+	return "unknown check"
+}
+
+// ErrValidation is wrapped by all errors of a [Validator].
+var ErrValidation = errors.New("key validation failed")
+
+// ValidationError is returned by [Validator.Validate], if at least one check
+// failed.
+type ValidationError struct {
+	Key    Key     // The rejected key.
+	Failed []Check // All failed checks, in the order of their constants.
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	_, _ = sb.WriteString(ErrValidation.Error())
+	_, _ = sb.WriteString(" for ")
+	_, _ = sb.WriteString(e.Key.String())
+	for i, c := range e.Failed {
+		if i == 0 {
+			_, _ = sb.WriteString(": ")
+		} else {
+			_, _ = sb.WriteString(", ")
+		}
+		_, _ = sb.WriteString(c.String())
+	}
+	return sb.String()
+}
+
+// Unwrap returns [ErrValidation].
+func (e *ValidationError) Unwrap() error { return ErrValidation }
+
+// Has returns true, if the given check failed.
+func (e *ValidationError) Has(c Check) bool { return slices.Contains(e.Failed, c) }
+
+// Validate checks the given key. If a check fails, a [*ValidationError] is
+// returned.
+func (v *Validator) Validate(key Key) error {
+	if key == Invalid {
+		return &ValidationError{Key: key, Failed: []Check{CheckZero}}
+	}
+	var failed []Check
+	ts := int64(uint64(key) >> v.layout.randomBits())
+	if ts < v.notBefore {
+		failed = append(failed, CheckBefore)
+	}
+	if v.future {
+		clock := v.clock
+		if clock == nil {
+			clock = SystemClock{}
+		}
+		if ts > v.layout.timestamp(clock.Now().Add(v.tolerance)) {
+			failed = append(failed, CheckFuture)
+		}
+	}
+	if v.layout.AppID(key) > v.maxAppID {
+		failed = append(failed, CheckAppID)
+	}
+	if len(failed) > 0 {
+		return &ValidationError{Key: key, Failed: failed}
+	}
+	return nil
+}
+
+// Parse parses the given string, see [Parse], and validates the resulting key.
+func (v *Validator) Parse(s string) (Key, error) {
+	key, err := Parse(s)
+	if err != nil {
+		return Invalid, err
+	}
+	if err = v.Validate(key); err != nil {
+		return Invalid, err
+	}
+	return key, nil
+}
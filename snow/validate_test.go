@@ -0,0 +1,117 @@
+// -----------------------------------------------------------------------------
+// Copyright (c) 2023-present Detlef Stern
+//
+// This file is part of Zero.
+//
+// Zero is licensed under the latest version of the EUPL (European Union Public
+// License). Please see file LICENSE.txt for your rights and obligations under
+// this license.
+//
+// SPDX-License-Identifier: EUPL-1.2
+// SPDX-FileCopyrightText: 2023-present Detlef Stern
+// -----------------------------------------------------------------------------
+
+package snow_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"t73f.de/r/zero/snow"
+)
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+	clock := newTestClock()
+	start := clock.now
+	generator := snow.New(4, snow.WithClock(clock))
+	validator := generator.Validator(
+		snow.RejectBefore(start.Add(-time.Hour)),
+		snow.RejectFuture(time.Minute),
+		snow.RejectAppIDAbove(2),
+	)
+	layout := generator.Layout()
+	compose := func(t time.Time, appID uint) snow.Key {
+		key, err := layout.Compose(snow.Parts{Time: t, AppID: appID})
+		if err != nil {
+			panic(err)
+		}
+		return key
+	}
+
+	var testcases = []struct {
+		name string
+		key  snow.Key
+		exp  []snow.Check
+	}{
+		{"created", generator.Create(1), nil},
+		{"start", compose(start.Add(-time.Hour), 0), nil},
+		{"tolerance", compose(start.Add(time.Minute), 2), nil},
+		{"zero", snow.Invalid, []snow.Check{snow.CheckZero}},
+		{"early", compose(start.Add(-time.Hour-time.Millisecond), 0), []snow.Check{snow.CheckBefore}},
+		{"future", compose(start.Add(time.Minute+time.Millisecond), 0), []snow.Check{snow.CheckFuture}},
+		{"appid", compose(start, 3), []snow.Check{snow.CheckAppID}},
+		{"all", compose(start.Add(-2*time.Hour), 15), []snow.Check{snow.CheckBefore, snow.CheckAppID}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validator.Validate(tc.key)
+			if tc.exp == nil {
+				if err != nil {
+					t.Errorf("no error expected, but got %v", err)
+				}
+				return
+			}
+			var verr *snow.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("validation error expected, but got %v", err)
+			}
+			if !errors.Is(err, snow.ErrValidation) {
+				t.Errorf("error should wrap ErrValidation: %v", err)
+			}
+			if verr.Key != tc.key {
+				t.Errorf("key %v expected, but got %v", tc.key, verr.Key)
+			}
+			if !slices.Equal(verr.Failed, tc.exp) {
+				t.Errorf("failed checks %v expected, but got %v", tc.exp, verr.Failed)
+			}
+			for _, c := range tc.exp {
+				if !verr.Has(c) {
+					t.Errorf("check %v should have failed", c)
+				}
+			}
+		})
+	}
+
+	// Time passes, so that the future key becomes valid.
+	future := compose(start.Add(time.Hour), 0)
+	if err := validator.Validate(future); err == nil {
+		t.Error("key from the future should be rejected")
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if err := validator.Validate(future); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidatorParse(t *testing.T) {
+	t.Parallel()
+	validator := snow.NewValidator(snow.NewLayout(0), snow.RejectFuture(0))
+	if _, err := validator.Parse("0U"); !errors.Is(err, snow.ErrBadChar) {
+		t.Errorf("ErrBadChar expected, but got %v", err)
+	}
+	if _, err := validator.Parse("0"); !errors.Is(err, snow.ErrValidation) {
+		t.Errorf("ErrValidation expected, but got %v", err)
+	}
+	if _, err := validator.Parse("FZZZZZZZZZZZZ"); !errors.Is(err, snow.ErrValidation) {
+		t.Errorf("ErrValidation expected, but got %v", err)
+	} else if exp := "key validation failed for FZZZZZZZZZZZZ: timestamp in the future"; err.Error() != exp {
+		t.Errorf("error message %q expected, but got %q", exp, err.Error())
+	}
+	key := snow.New(0).Create(0)
+	if got, err := validator.Parse(key.String()); err != nil || got != key {
+		t.Errorf("key %v expected, but got %v (err: %v)", key, got, err)
+	}
+}